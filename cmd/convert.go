@@ -1,43 +1,55 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"github.com/sliveryou/grom/util"
 )
 
 var (
-	filePath       string
 	outputFilePath string
+	convertFormat  string
+	schemaFile     string
+	snapshotFile   string
 	packageName    string
 	structName     string
-	host           string
-	port           int
-	user           string
-	password       string
-	database       string
 	table          string
 	enable         []string
-
-	validServices = map[string]struct{}{
-		"INITIALISM":       {},
-		"FIELD_COMMENT":    {},
-		"SQL_NULL":         {},
-		"GUREGU_NULL":      {},
-		"JSON_TAG":         {},
-		"XML_TAG":          {},
-		"GORM_TAG":         {},
-		"XORM_TAG":         {},
-		"BEEGO_TAG":        {},
-		"GOROSE_TAG":       {},
-		"GORM_V2_TAG":      {},
-		"DISABLE_UNSIGNED": {},
+	tablePrefixes  []string
+	tableSuffixes  []string
+	initialisms    []string
+	verbose        bool
+
+	// validServices the services can be enabled, in the order of the help information.
+	validServices = []string{
+		"INITIALISM",
+		"FIELD_COMMENT",
+		"SQL_NULL",
+		"GUREGU_NULL",
+		"JSON_TAG",
+		"XML_TAG",
+		"GORM_TAG",
+		"XORM_TAG",
+		"BEEGO_TAG",
+		"GOROSE_TAG",
+		"GORM_V2_TAG",
+		"VALIDATE_TAG",
+		"YAML_TAG",
+		"TOML_TAG",
+		"BSON_TAG",
+		"MSGPACK_TAG",
+		"MAPSTRUCTURE_TAG",
+		"FORM_TAG",
+		"SINGULAR_TABLE",
+		"VIEW_DEFINITION",
+		"CONSTRUCTOR",
+		"DISABLE_UNSIGNED",
 	}
 )
 
@@ -46,35 +58,71 @@ var convertCmd = &cobra.Command{
 	Short: "Convert mysql table fields to golang model structure",
 	Long:  "Convert mysql table fields to golang model structure by information_schema.columns and information_schema.statistics",
 	Example: "  grom convert -n ./grom.json\n" +
+		"  grom convert -n ./grom.yaml --profile dev\n" +
+		"  grom convert --dsn 'user:password@tcp(localhost:3306)/database?charset=utf8mb4' -t table -e JSON_TAG,GORM_V2_TAG\n" +
+		"  grom convert -H localhost -P 3306 -u user -p -d database -t table\n" +
+		"  grom convert --defaults-file ~/.my.cnf -d database -t table\n" +
+		"  grom convert -n ./grom.json -f json -o schema.json\n" +
+		"  grom convert -n ./grom.json --from-schema schema.json\n" +
+		"  grom convert -n ./grom.json --from-snapshot grom.snapshot.json -t table\n" +
 		"  grom convert -H localhost -P 3306 -u user -p password -d database -t table -e INITIALISM,FIELD_COMMENT,JSON_TAG,GORM_V2_TAG --package PACKAGE_NAME --struct STRUCT_NAME",
 	RunE: convertFunc,
 }
 
 func init() {
-	convertCmd.Flags().StringVar(&packageName, "package", "", "the package name of the converted model structure")
-	convertCmd.Flags().StringVar(&structName, "struct", "", "the struct name of the converted model structure")
-	convertCmd.Flags().StringVarP(&filePath, "name", "n", "", "the name of the grom configuration file")
+	addConvertFlags(convertCmd.Flags())
 	convertCmd.Flags().StringVarP(&outputFilePath, "output", "o", "", "the name of the file used to store the grom output")
-	convertCmd.Flags().StringVarP(&host, "host", "H", "", "the host of mysql")
-	convertCmd.Flags().IntVarP(&port, "port", "P", 0, "the port of mysql")
-	convertCmd.Flags().StringVarP(&user, "user", "u", "", "the user of mysql")
-	convertCmd.Flags().StringVarP(&password, "password", "p", "", "the password of mysql")
-	convertCmd.Flags().StringVarP(&database, "database", "d", "", "the database of mysql")
-	convertCmd.Flags().StringVarP(&table, "table", "t", "", "the table of mysql")
-	convertCmd.Flags().StringSliceVarP(&enable, "enable", "e", nil, "enable services (must in [INITIALISM,FIELD_COMMENT,SQL_NULL,GUREGU_NULL,JSON_TAG,XML_TAG,GORM_TAG,XORM_TAG,BEEGO_TAG,GOROSE_TAG,GORM_V2_TAG,DISABLE_UNSIGNED])")
-
+	convertCmd.Flags().StringVarP(&convertFormat, "format", "f", outputFormatGo, "the output format, must in [go,json], json outputs the versioned schema with the derived fields")
+	convertCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "print the details of conversion, such as the tables not embedding the base model")
 	rootCmd.AddCommand(convertCmd)
 }
 
+// addConvertFlags adds the flags of the converted model structure, the grom configuration file and the mysql connection.
+func addConvertFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&packageName, "package", "", "the package name of the converted model structure")
+	fs.StringVar(&structName, "struct", "", "the struct name of the converted model structure")
+	addConfigFileFlags(fs)
+	addDBFlags(fs)
+	fs.StringVarP(&table, "table", "t", "", "the table of mysql")
+	fs.StringVar(&schemaFile, "from-schema", "", "the json schema file output by --format json, which is used instead of connecting to mysql")
+	fs.StringVar(&snapshotFile, "from-snapshot", "", "the snapshot file output by grom snapshot, which is used instead of connecting to mysql")
+	fs.StringSliceVar(&tablePrefixes, "table-prefix", nil, "the table prefixes stripped from the struct name, such as t_,tbl_")
+	fs.StringSliceVar(&tableSuffixes, "table-suffix", nil, "the table suffixes stripped from the struct name, such as _tab")
+	fs.StringSliceVar(&initialisms, "initialisms", nil, "the initialisms added to or removed (starting with -) from the INITIALISM service, such as SKU,OAuth,-ID")
+	fs.StringSliceVarP(&enable, "enable", "e", nil, "enable services (must in [INITIALISM,FIELD_COMMENT,SQL_NULL,GUREGU_NULL,JSON_TAG,XML_TAG,GORM_TAG,XORM_TAG,BEEGO_TAG,GOROSE_TAG,GORM_V2_TAG,VALIDATE_TAG,YAML_TAG,TOML_TAG,BSON_TAG,MSGPACK_TAG,MAPSTRUCTURE_TAG,FORM_TAG,SINGULAR_TABLE,VIEW_DEFINITION,CONSTRUCTOR,DISABLE_UNSIGNED])")
+}
+
 func convertFunc(_ *cobra.Command, _ []string) error {
+	defer util.CloseDB()
+
 	config, err := getCmdConfig()
 	if err != nil {
 		return errors.WithMessage(err, "getCmdConfig err")
 	}
+	if err = util.ValidateCmdConfig(config); err != nil {
+		return err
+	}
+	config.Verbose = verbose
 
-	out, err := util.ConvertTable(*config)
-	if err != nil {
-		return errors.WithMessage(err, "util.ConvertTable err")
+	var out string
+	switch strings.ToLower(convertFormat) {
+	case outputFormatGo:
+		out, err = util.ConvertTable(*config)
+		if err != nil {
+			return errors.WithMessage(err, "util.ConvertTable err")
+		}
+	case util.ConfigFormatJSON:
+		schema, err := util.GetSchema(config)
+		if err != nil {
+			return errors.WithMessage(err, "util.GetSchema err")
+		}
+		b, err := util.MarshalValue(schema, util.ConfigFormatJSON)
+		if err != nil {
+			return errors.WithMessage(err, "util.MarshalValue err")
+		}
+		out = string(b)
+	default:
+		return errors.New("output format must in [go,json], format: " + convertFormat)
 	}
 
 	if outputFilePath != "" {
@@ -97,18 +145,9 @@ func saveOutputToFile(out string) error {
 }
 
 func getCmdConfig() (*util.CmdConfig, error) {
-	config := util.CmdConfig{}
-
-	if filePath != "" {
-		content, err := os.ReadFile(filePath)
-		if err != nil {
-			return nil, errors.WithMessage(err, "os.ReadFile err")
-		}
-
-		err = json.Unmarshal(content, &config)
-		if err != nil {
-			return nil, errors.WithMessage(err, "json.Unmarshal err")
-		}
+	config, err := getConnectionConfig()
+	if err != nil {
+		return nil, err
 	}
 
 	if packageName != "" {
@@ -117,60 +156,98 @@ func getCmdConfig() (*util.CmdConfig, error) {
 	if structName != "" {
 		config.StructName = structName
 	}
-	if host != "" {
-		config.Host = host
+	if table != "" {
+		config.Table = table
 	}
-	if port != 0 {
-		config.Port = port
+	if schemaFile != "" {
+		config.SchemaFile = schemaFile
 	}
-	if user != "" {
-		config.User = user
+	if snapshotFile != "" {
+		config.SnapshotFile = snapshotFile
 	}
-	if password != "" {
-		config.Password = password
+	if len(tablePrefixes) != 0 {
+		config.TablePrefixes = tablePrefixes
 	}
-	if database != "" {
-		config.Database = database
+	if len(tableSuffixes) != 0 {
+		config.TableSuffixes = tableSuffixes
 	}
-	if table != "" {
-		config.Table = table
+	if len(initialisms) != 0 {
+		config.Initialisms = append(config.Initialisms, initialisms...)
+	}
+
+	if err = enableServices(config, enable); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// enableServices enables the services of config, the service names are case-insensitive.
+func enableServices(config *util.CmdConfig, services []string) error {
+	for _, v := range services {
+		service := strings.ToUpper(v)
+		if !isValidService(service) {
+			return errors.New("enabled service is invalid, service: " + service)
+		}
+
+		switch service {
+		case "INITIALISM":
+			config.EnableInitialism = true
+		case "FIELD_COMMENT":
+			config.EnableFieldComment = true
+		case "SQL_NULL":
+			config.EnableSQLNull = true
+		case "GUREGU_NULL":
+			config.EnableGureguNull = true
+		case "JSON_TAG":
+			config.EnableJSONTag = true
+		case "XML_TAG":
+			config.EnableXMLTag = true
+		case "GORM_TAG":
+			config.EnableGormTag = true
+		case "XORM_TAG":
+			config.EnableXormTag = true
+		case "BEEGO_TAG":
+			config.EnableBeegoTag = true
+		case "GOROSE_TAG":
+			config.EnableGoroseTag = true
+		case "GORM_V2_TAG":
+			config.EnableGormV2Tag = true
+		case "VALIDATE_TAG":
+			config.EnableValidateTag = true
+		case "YAML_TAG":
+			config.EnableYAMLTag = true
+		case "TOML_TAG":
+			config.EnableTOMLTag = true
+		case "BSON_TAG":
+			config.EnableBSONTag = true
+		case "MSGPACK_TAG":
+			config.EnableMsgpackTag = true
+		case "MAPSTRUCTURE_TAG":
+			config.EnableMapstructureTag = true
+		case "FORM_TAG":
+			config.EnableFormTag = true
+		case "SINGULAR_TABLE":
+			config.EnableSingularTable = true
+		case "VIEW_DEFINITION":
+			config.EnableViewDefinition = true
+		case "CONSTRUCTOR":
+			config.EnableConstructor = true
+		case "DISABLE_UNSIGNED":
+			config.DisableUnsigned = true
+		}
 	}
 
-	if len(enable) != 0 {
-		for _, v := range enable {
-			service := strings.ToUpper(v)
-			if _, ok := validServices[service]; !ok {
-				return nil, errors.New("enabled service is invalid, service: " + service)
-			}
-
-			switch service {
-			case "INITIALISM":
-				config.EnableInitialism = true
-			case "FIELD_COMMENT":
-				config.EnableFieldComment = true
-			case "SQL_NULL":
-				config.EnableSQLNull = true
-			case "GUREGU_NULL":
-				config.EnableGureguNull = true
-			case "JSON_TAG":
-				config.EnableJSONTag = true
-			case "XML_TAG":
-				config.EnableXMLTag = true
-			case "GORM_TAG":
-				config.EnableGormTag = true
-			case "XORM_TAG":
-				config.EnableXormTag = true
-			case "BEEGO_TAG":
-				config.EnableBeegoTag = true
-			case "GOROSE_TAG":
-				config.EnableGoroseTag = true
-			case "GORM_V2_TAG":
-				config.EnableGormV2Tag = true
-			case "DISABLE_UNSIGNED":
-				config.DisableUnsigned = true
-			}
+	return nil
+}
+
+// isValidService reports whether the service is in validServices.
+func isValidService(service string) bool {
+	for _, s := range validServices {
+		if s == service {
+			return true
 		}
 	}
 
-	return &config, nil
+	return false
 }
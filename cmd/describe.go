@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/sliveryou/grom/util"
+)
+
+var describeCmd = &cobra.Command{
+	Use:   "describe <table>",
+	Short: "Describe mysql table columns, indexes and foreign keys",
+	Long:  "Describe mysql table columns, indexes, check constraints and foreign keys seen by grom, which are queried by information_schema.columns, information_schema.statistics, information_schema.check_constraints and information_schema.key_column_usage",
+	Args:  cobra.ExactArgs(1),
+	Example: "  grom describe table -n ./grom.json\n" +
+		"  grom describe table -H localhost -u user -p -d database -f yaml",
+	RunE: describeFunc,
+}
+
+func init() {
+	addConfigFileFlags(describeCmd.Flags())
+	addDBFlags(describeCmd.Flags())
+	describeCmd.Flags().StringVarP(&outputFormat, "format", "f", outputFormatTable, "the output format, must in [table,json,yaml]")
+	rootCmd.AddCommand(describeCmd)
+}
+
+func describeFunc(_ *cobra.Command, args []string) error {
+	defer util.CloseDB()
+
+	config, err := getConnectionConfig()
+	if err != nil {
+		return errors.WithMessage(err, "getConnectionConfig err")
+	}
+	config.Table = args[0]
+
+	schema, err := util.DescribeTable(config)
+	if err != nil {
+		return errors.WithMessage(err, "util.DescribeTable err")
+	}
+
+	return printOutput(schema, func(w io.Writer) {
+		printRow(w, "TABLE", schema.Name)
+		printRow(w, "COMMENT", schema.Comment)
+		printRow(w)
+		printRow(w, "COLUMN", "TYPE", "DATA_TYPE", "NULLABLE", "KEY", "DEFAULT", "EXTRA", "COMMENT")
+		for _, ci := range schema.Columns {
+			var key, extra []string
+			if ci.IsPrimaryKey {
+				key = append(key, "PRI")
+			}
+			if len(ci.UniqueIndexes) != 0 {
+				key = append(key, "UNI")
+			}
+			if len(ci.Indexes) != 0 {
+				key = append(key, "MUL")
+			}
+			if ci.IsAutoIncrement {
+				extra = append(extra, "auto_increment")
+			}
+			if ci.IsUnsigned {
+				extra = append(extra, "unsigned")
+			}
+			if ci.Generated != "" {
+				extra = append(extra, strings.ToLower(ci.Generated)+" generated as ("+ci.GenerationExpression+")")
+			}
+			if ci.IsDefaultGenerated {
+				extra = append(extra, "default_generated")
+			}
+			if ci.IsInvisible {
+				extra = append(extra, "invisible")
+			}
+			printRow(w, ci.Name, ci.Type, ci.DataType, ci.IsNullable, strings.Join(key, ","), ci.Default, strings.Join(extra, ","), ci.Comment)
+		}
+
+		if len(schema.Indexes) != 0 {
+			printRow(w)
+			printRow(w, "INDEX", "COLUMN", "SEQUENCE", "SUB_PART", "TYPE", "UNIQUE", "COMMENT")
+			for _, ii := range schema.Indexes {
+				subPart := ""
+				if ii.SubPart != 0 {
+					subPart = strconv.FormatInt(ii.SubPart, 10)
+				}
+				printRow(w, ii.Name, ii.ColumnName, ii.Sequence, subPart, ii.Type, ii.IsUnique, ii.Comment)
+			}
+		}
+
+		if len(schema.Checks) != 0 {
+			printRow(w)
+			printRow(w, "CHECK", "CLAUSE")
+			for _, check := range schema.Checks {
+				printRow(w, check.Name, check.Clause)
+			}
+		}
+
+		if len(schema.ForeignKeys) != 0 {
+			printRow(w)
+			printRow(w, "FOREIGN_KEY", "COLUMN", "SEQUENCE", "REFERENCED_TABLE", "REFERENCED_COLUMN")
+			for _, fk := range schema.ForeignKeys {
+				printRow(w, fk.Name, fk.ColumnName, fk.Sequence, fk.ReferencedTable, fk.ReferencedColumn)
+			}
+		}
+	})
+}
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
+
+	"github.com/sliveryou/grom/util"
+)
+
+// passwordPrompt the value of the password flag used without value, which means prompting the password.
+const passwordPrompt = "*"
+
+var (
+	filePath     string
+	profile      string
+	dsn          string
+	host         string
+	port         int
+	socket       string
+	charset      string
+	tlsMode      string
+	tlsCA        string
+	tlsCert      string
+	tlsKey       string
+	params       map[string]string
+	user         string
+	password     string
+	passwordFile string
+	defaultsFile string
+	loginPath    string
+	database     string
+)
+
+// addConfigFileFlags adds the flags of the grom configuration file.
+func addConfigFileFlags(fs *pflag.FlagSet) {
+	fs.StringVarP(&filePath, "name", "n", "", "the name of the grom configuration file")
+	fs.StringVar(&profile, "profile", "", "the profile name of the grom configuration file, such as dev and staging")
+}
+
+// addDBFlags adds the flags of the mysql connection.
+func addDBFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&dsn, "dsn", "", "the full dsn of mysql, such as user:password@tcp(localhost:3306)/database")
+	fs.StringVarP(&host, "host", "H", "", "the host of mysql")
+	fs.IntVarP(&port, "port", "P", 0, "the port of mysql")
+	fs.StringVarP(&socket, "socket", "S", "", "the unix socket path of mysql")
+	fs.StringVar(&charset, "charset", "", "the charset of mysql connection, such as utf8mb4 (default utf8)")
+	fs.StringVar(&tlsMode, "tls", "", "the tls mode of mysql connection, must in [true,false,skip-verify,preferred]")
+	fs.StringVar(&tlsCA, "tls-ca", "", "the tls ca file of mysql connection")
+	fs.StringVar(&tlsCert, "tls-cert", "", "the tls cert file of mysql connection")
+	fs.StringVar(&tlsKey, "tls-key", "", "the tls key file of mysql connection")
+	fs.StringToStringVar(&params, "params", nil, "the extra params of mysql connection, such as timeout=5s,collation=utf8mb4_general_ci")
+	fs.StringVarP(&user, "user", "u", "", "the user of mysql")
+	fs.StringVarP(&password, "password", "p", "", "the password of mysql, prompt it if the flag is used without value, such as -p before another flag or at the end")
+	fs.Lookup("password").NoOptDefVal = passwordPrompt
+	fs.StringVar(&passwordFile, "password-file", "", "the file containing the password of mysql, - means reading from stdin")
+	fs.StringVar(&defaultsFile, "defaults-file", "", "the mysql option file whose [client] section will be read (default ~/.my.cnf)")
+	fs.StringVar(&loginPath, "login-path", "", "the login path of ~/.mylogin.cnf generated by mysql_config_editor")
+	fs.StringVarP(&database, "database", "d", "", "the database of mysql")
+}
+
+// normalizePasswordArgs returns the arguments with the legacy usage -p password and --password password
+// rewritten to --password=password, which is otherwise parsed as the password prompt and an argument.
+func normalizePasswordArgs(args []string) []string {
+	normalized := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return append(normalized, args[i:]...)
+		}
+		if (arg == "-p" || arg == "--password") && i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+			arg = "--password=" + args[i+1]
+			i++
+		}
+		normalized = append(normalized, arg)
+	}
+
+	return normalized
+}
+
+// loadCmdConfig loads the grom configuration file and overrides it by the GROM_* environment variables.
+func loadCmdConfig() (*util.CmdConfig, error) {
+	config := &util.CmdConfig{}
+
+	if filePath != "" {
+		var err error
+		config, err = util.LoadCmdConfig(filePath, profile)
+		if err != nil {
+			return nil, errors.WithMessage(err, "util.LoadCmdConfig err")
+		}
+	} else if profile != "" {
+		return nil, errors.New("profile must be used with the grom configuration file")
+	}
+
+	if err := util.ApplyEnvConfig(config); err != nil {
+		return nil, errors.WithMessage(err, "util.ApplyEnvConfig err")
+	}
+
+	return config, nil
+}
+
+// getConnectionConfig returns the config of the grom configuration file overridden by the GROM_* environment
+// variables and the flags of the mysql connection, and the credentials are filled.
+func getConnectionConfig() (*util.CmdConfig, error) {
+	config, err := loadCmdConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = applyDBFlags(&config.DBConfig); err != nil {
+		return nil, err
+	}
+	if err = util.ApplyCredentials(&config.DBConfig, os.Stdin); err != nil {
+		return nil, errors.WithMessage(err, "util.ApplyCredentials err")
+	}
+
+	return config, nil
+}
+
+// applyDBFlags overrides the db config by the flags of the mysql connection,
+// the password will be prompted if the password flag is used without value.
+func applyDBFlags(dc *util.DBConfig) error {
+	if dsn != "" {
+		dc.DSN = dsn
+	}
+	if host != "" {
+		dc.Host = host
+	}
+	if port != 0 {
+		dc.Port = port
+	}
+	if socket != "" {
+		dc.Socket = socket
+	}
+	if charset != "" {
+		dc.Charset = charset
+	}
+	if tlsMode != "" {
+		dc.TLS = tlsMode
+	}
+	if tlsCA != "" {
+		dc.TLSCA = tlsCA
+	}
+	if tlsCert != "" {
+		dc.TLSCert = tlsCert
+	}
+	if tlsKey != "" {
+		dc.TLSKey = tlsKey
+	}
+	if len(params) != 0 {
+		if dc.Params == nil {
+			dc.Params = make(map[string]string, len(params))
+		}
+		for k, v := range params {
+			dc.Params[k] = v
+		}
+	}
+	if user != "" {
+		dc.User = user
+	}
+	if passwordFile != "" {
+		dc.PasswordFile = passwordFile
+		dc.Password = ""
+	}
+	if defaultsFile != "" {
+		dc.DefaultsFile = defaultsFile
+	}
+	if loginPath != "" {
+		dc.LoginPath = loginPath
+	}
+	if database != "" {
+		dc.Database = database
+	}
+
+	switch password {
+	case "":
+	case passwordPrompt:
+		p, err := promptPassword()
+		if err != nil {
+			return err
+		}
+		dc.Password, dc.PasswordFile = p, ""
+	default:
+		dc.Password, dc.PasswordFile = password, ""
+	}
+
+	return nil
+}
+
+// promptPassword prompts the password of mysql on the terminal without echo.
+func promptPassword() (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", errors.New("password prompt requires a terminal, use --password-file instead")
+	}
+
+	fmt.Fprint(os.Stderr, "Enter password: ")
+	b, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", errors.WithMessage(err, "term.ReadPassword err")
+	}
+
+	return string(b), nil
+}
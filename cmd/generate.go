@@ -1,9 +1,9 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -12,25 +12,39 @@ import (
 )
 
 var (
-	fileInfo string
-	fileName string
+	fileInfo   string
+	fileName   string
+	fileFormat string
 )
 
 var generateCmd = &cobra.Command{
-	Use:     "generate",
-	Short:   "Generate grom configuration file",
-	Long:    "Generate grom configuration file like this:\n" + generateFileInfo(),
-	Example: "  grom generate -n ./grom.json",
-	RunE:    generateFunc,
+	Use:   "generate",
+	Short: "Generate grom configuration file",
+	Long:  "Generate grom configuration file like this:\n" + generateFileInfo(),
+	Example: "  grom generate -n ./grom.json\n" +
+		"  grom generate -n ./grom.yaml\n" +
+		"  grom generate -n ./grom.conf --format toml",
+	RunE: generateFunc,
 }
 
 func init() {
 	generateCmd.Flags().StringVarP(&fileName, "name", "n", "grom.json", "the name of the generated grom configuration file")
+	generateCmd.Flags().StringVarP(&fileFormat, "format", "f", "", "the format of the generated grom configuration file, must in [json,yaml,toml], detected by the file extension by default")
 	rootCmd.AddCommand(generateCmd)
 }
 
 func generateFunc(_ *cobra.Command, _ []string) error {
-	err := os.WriteFile(fileName, []byte(fileInfo), writeFilePerm)
+	format := fileFormat
+	if format == "" {
+		format = util.GetConfigFormat(fileName)
+	}
+
+	content, err := util.MarshalCmdConfig(sampleCmdConfig(), strings.ToLower(format))
+	if err != nil {
+		return errors.WithMessage(err, "util.MarshalCmdConfig err")
+	}
+
+	err = os.WriteFile(fileName, content, writeFilePerm)
 	if err != nil {
 		return errors.WithMessage(err, "os.WriteFile err")
 	}
@@ -41,33 +55,45 @@ func generateFunc(_ *cobra.Command, _ []string) error {
 }
 
 func generateFileInfo() string {
-	c := util.CmdConfig{
+	b, _ := util.MarshalCmdConfig(sampleCmdConfig(), util.ConfigFormatJSON)
+	fileInfo = string(b)
+
+	return fileInfo
+}
+
+// sampleCmdConfig returns the sample config of the generated grom configuration file.
+func sampleCmdConfig() *util.CmdConfig {
+	return &util.CmdConfig{
 		DBConfig: util.DBConfig{
 			Host:     "localhost",
 			Port:     3306,
 			User:     "user",
-			Password: "password",
 			Database: "database",
 			Table:    "table",
 		},
-		PackageName:        "package_name",
-		StructName:         "struct_name",
-		EnableInitialism:   true,
-		EnableFieldComment: true,
-		EnableSQLNull:      false,
-		EnableGureguNull:   false,
-		EnableJSONTag:      true,
-		EnableXMLTag:       false,
-		EnableGormTag:      false,
-		EnableXormTag:      false,
-		EnableBeegoTag:     false,
-		EnableGoroseTag:    false,
-		EnableGormV2Tag:    true,
-		DisableUnsigned:    false,
+		PackageName:           "package_name",
+		StructName:            "struct_name",
+		EnableInitialism:      true,
+		EnableFieldComment:    true,
+		EnableSQLNull:         false,
+		EnableGureguNull:      false,
+		EnableJSONTag:         true,
+		EnableXMLTag:          false,
+		EnableGormTag:         false,
+		EnableXormTag:         false,
+		EnableBeegoTag:        false,
+		EnableGoroseTag:       false,
+		EnableGormV2Tag:       true,
+		EnableValidateTag:     false,
+		EnableYAMLTag:         false,
+		EnableTOMLTag:         false,
+		EnableBSONTag:         false,
+		EnableMsgpackTag:      false,
+		EnableMapstructureTag: false,
+		EnableFormTag:         false,
+		EnableSingularTable:   false,
+		EnableViewDefinition:  false,
+		EnableConstructor:     false,
+		DisableUnsigned:       false,
 	}
-
-	b, _ := json.MarshalIndent(&c, "", "    ")
-	fileInfo = string(b)
-
-	return fileInfo
 }
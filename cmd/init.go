@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/gookit/color"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/sliveryou/grom/util"
+)
+
+var (
+	initFileName   string
+	initFileFormat string
+	nonInteractive bool
+	forceWrite     bool
+
+	// defaultServices the services enabled by default, which are the same as the generated sample.
+	defaultServices = []string{"INITIALISM", "FIELD_COMMENT", "JSON_TAG", "GORM_V2_TAG"}
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Initialize grom configuration file interactively",
+	Long: "Initialize grom configuration file by asking the connection details, testing the connection, " +
+		"choosing the database, table and services, the password will not be written into the file",
+	Example: "  grom init\n" +
+		"  grom init -n ./grom.yaml -H localhost -u user -p\n" +
+		"  grom init --non-interactive -n ./grom.json -H localhost -u user -p password -d database -t table -e JSON_TAG,GORM_V2_TAG",
+	RunE: initFunc,
+}
+
+func init() {
+	initCmd.Flags().StringVarP(&initFileName, "name", "n", "grom.json", "the name of the initialized grom configuration file")
+	initCmd.Flags().StringVarP(&initFileFormat, "format", "f", "", "the format of the initialized grom configuration file, must in [json,yaml,toml], detected by the file extension by default")
+	initCmd.Flags().BoolVar(&nonInteractive, "non-interactive", false, "initialize by the flags without asking, the database and table flags are required")
+	initCmd.Flags().BoolVar(&forceWrite, "force", false, "overwrite the existing grom configuration file")
+	addDBFlags(initCmd.Flags())
+	initCmd.Flags().StringVarP(&table, "table", "t", "", "the table of mysql")
+	initCmd.Flags().StringVar(&packageName, "package", "", "the package name of the converted model structure")
+	initCmd.Flags().StringVar(&structName, "struct", "", "the struct name of the converted model structure")
+	initCmd.Flags().StringSliceVarP(&enable, "enable", "e", nil, "enable services (must in ["+strings.Join(validServices, ",")+"], default "+strings.Join(defaultServices, ",")+")")
+
+	rootCmd.AddCommand(initCmd)
+}
+
+func initFunc(_ *cobra.Command, _ []string) error {
+	defer util.CloseDB()
+
+	format := strings.ToLower(initFileFormat)
+	if format == "" {
+		format = util.GetConfigFormat(initFileName)
+	}
+
+	config := &util.CmdConfig{PackageName: packageName, StructName: structName}
+	config.Table = table
+	if err := applyDBFlags(&config.DBConfig); err != nil {
+		return err
+	}
+	services := enable
+	if len(services) == 0 {
+		services = defaultServices
+	}
+
+	if nonInteractive {
+		if config.Table == "" {
+			return errors.New("table must be specified in non-interactive mode")
+		}
+		if err := pingInitDB(config); err != nil {
+			return err
+		}
+	} else {
+		var err error
+		services, err = askInitConfig(newPrompter(os.Stdin, os.Stdout), config, services)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := enableServices(config, services); err != nil {
+		return err
+	}
+	if config.PackageName == "" {
+		config.PackageName = "model"
+	}
+	if err := util.ValidateCmdConfig(config); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(initFileName); err == nil && !forceWrite {
+		return errors.New("grom configuration file already exists, use --force to overwrite it: " + initFileName)
+	}
+
+	// the password is provided by the prompt, password file or environment variable when converting
+	hasPassword := config.Password != ""
+	config.Password = ""
+	if config.DSN != "" {
+		dsn, hasDSNPassword, err := removeDSNPassword(config.DSN)
+		if err != nil {
+			return err
+		}
+		config.DSN, hasPassword = dsn, hasPassword || hasDSNPassword
+	}
+
+	content, err := util.MarshalCmdConfig(config, format)
+	if err != nil {
+		return errors.WithMessage(err, "util.MarshalCmdConfig err")
+	}
+
+	err = os.WriteFile(initFileName, content, writeFilePerm)
+	if err != nil {
+		return errors.WithMessage(err, "os.WriteFile err")
+	}
+
+	fmt.Println("write in:", initFileName)
+	if hasPassword {
+		color.Yellow.Println("the password is not written, provide it by -p, --password-file or GROM_PASSWORD when converting")
+	}
+
+	return nil
+}
+
+// askInitConfig asks the connection details, database, table, package name,
+// struct name and services, the connection will be tested before choosing the database.
+func askInitConfig(p *prompter, config *util.CmdConfig, services []string) ([]string, error) {
+	var err error
+	dc := &config.DBConfig
+
+	if dc.DSN == "" && dc.Socket == "" && dc.LoginPath == "" {
+		if dc.Host, err = p.ask("MySQL host", defaultString(dc.Host, "localhost")); err != nil {
+			return nil, err
+		}
+		if dc.Port, err = p.askInt("MySQL port", defaultInt(dc.Port, 3306)); err != nil {
+			return nil, err
+		}
+		if dc.User, err = p.ask("MySQL user", dc.User); err != nil {
+			return nil, err
+		}
+		if dc.Password == "" && dc.PasswordFile == "" {
+			if dc.Password, err = p.askPassword("MySQL password (empty to read from option files)"); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err = pingInitDB(config); err != nil {
+		return nil, err
+	}
+	color.Green.Println("connection is ok")
+
+	databases, err := util.GetDatabases(config)
+	if err != nil {
+		return nil, errors.WithMessage(err, "util.GetDatabases err")
+	}
+	if config.Database, err = p.choose("Database", databases, config.Database); err != nil {
+		return nil, err
+	}
+
+	tables, err := util.GetTables(config)
+	if err != nil {
+		return nil, errors.WithMessage(err, "util.GetTables err")
+	}
+	if len(tables) == 0 {
+		return nil, errors.New("no table is found in database: " + config.Database)
+	}
+	if config.Table, err = p.choose("Table", tables, config.Table); err != nil {
+		return nil, err
+	}
+
+	if config.PackageName, err = p.ask("Package name", defaultString(config.PackageName, "model")); err != nil {
+		return nil, err
+	}
+	if config.StructName, err = p.ask("Struct name (empty to convert from the table name)", config.StructName); err != nil {
+		return nil, err
+	}
+
+	return p.chooseMulti("Services (comma separated numbers or names)", validServices, services)
+}
+
+// pingInitDB tests the connection of the config, the credentials of the password file and
+// mysql option files are applied to the copy of config to keep them out of the written file,
+// and the opened connection will be reused by the following queries.
+func pingInitDB(config *util.CmdConfig) error {
+	cc := *config
+	if err := util.ApplyCredentials(&cc.DBConfig, os.Stdin); err != nil {
+		return errors.WithMessage(err, "util.ApplyCredentials err")
+	}
+
+	if err := util.PingDB(&cc); err != nil {
+		return errors.WithMessage(err, "util.PingDB err")
+	}
+	if config.Database == "" {
+		// the database of the dsn
+		config.Database = cc.Database
+	}
+
+	return nil
+}
+
+// removeDSNPassword returns the dsn without the password, and reports whether the dsn contains the password.
+func removeDSNPassword(dsn string) (string, bool, error) {
+	mc, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return "", false, errors.WithMessage(err, "mysql.ParseDSN err")
+	}
+	if mc.Passwd == "" {
+		return dsn, false, nil
+	}
+	mc.Passwd = ""
+
+	return mc.FormatDSN(), true, nil
+}
+
+// defaultString returns the default value if the string is empty.
+func defaultString(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+// defaultInt returns the default value if the integer is zero.
+func defaultInt(i, def int) int {
+	if i == 0 {
+		return def
+	}
+	return i
+}
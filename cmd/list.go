@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/sliveryou/grom/util"
+)
+
+var listCmd = &cobra.Command{
+	Use:       "list databases|tables",
+	Short:     "List mysql databases or tables",
+	Long:      "List mysql databases, or tables of the database with row estimates and comments by information_schema.tables",
+	ValidArgs: []string{"databases", "tables"},
+	Args:      cobra.ExactValidArgs(1),
+	Example: "  grom list databases -H localhost -u user -p\n" +
+		"  grom list tables -n ./grom.json\n" +
+		"  grom list tables -H localhost -u user -p -d database -f json",
+	RunE: listFunc,
+}
+
+func init() {
+	addConfigFileFlags(listCmd.Flags())
+	addDBFlags(listCmd.Flags())
+	listCmd.Flags().StringVarP(&outputFormat, "format", "f", outputFormatTable, "the output format, must in [table,json,yaml]")
+	rootCmd.AddCommand(listCmd)
+}
+
+func listFunc(_ *cobra.Command, args []string) error {
+	defer util.CloseDB()
+
+	config, err := getConnectionConfig()
+	if err != nil {
+		return errors.WithMessage(err, "getConnectionConfig err")
+	}
+
+	if args[0] == "databases" {
+		databases, err := util.GetDatabases(config)
+		if err != nil {
+			return errors.WithMessage(err, "util.GetDatabases err")
+		}
+
+		return printOutput(databases, func(w io.Writer) {
+			printRow(w, "DATABASE")
+			for _, database := range databases {
+				printRow(w, database)
+			}
+		})
+	}
+
+	if config.Database == "" && config.DSN == "" {
+		return errors.New("database must be specified to list tables")
+	}
+	tableInfos, err := util.GetTableInfos(config)
+	if err != nil {
+		return errors.WithMessage(err, "util.GetTableInfos err")
+	}
+
+	return printOutput(tableInfos, func(w io.Writer) {
+		printRow(w, "TABLE", "TYPE", "ROWS", "COMMENT")
+		for _, ti := range tableInfos {
+			printRow(w, ti.Name, ti.Type, ti.Rows, ti.Comment)
+		}
+	})
+}
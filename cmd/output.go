@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+
+	"github.com/sliveryou/grom/util"
+)
+
+const (
+	// outputFormatTable the output format of the aligned table, which is readable for humans.
+	outputFormatTable = "table"
+	// outputFormatGo the output format of the converted golang model structure.
+	outputFormatGo = "go"
+)
+
+var outputFormat string
+
+// printOutput prints the value in json or yaml format, or renders it by the table writer.
+func printOutput(v interface{}, renderTable func(w io.Writer)) error {
+	switch format := strings.ToLower(outputFormat); format {
+	case outputFormatTable:
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		renderTable(w)
+		if err := w.Flush(); err != nil {
+			return errors.WithMessage(err, "w.Flush err")
+		}
+	case util.ConfigFormatJSON, util.ConfigFormatYAML:
+		b, err := util.MarshalValue(v, format)
+		if err != nil {
+			return errors.WithMessage(err, "util.MarshalValue err")
+		}
+		fmt.Println(strings.TrimRight(string(b), "\n"))
+	default:
+		return errors.New("output format must in [table,json,yaml], format: " + outputFormat)
+	}
+
+	return nil
+}
+
+// printRow prints the tab separated columns of the table row.
+func printRow(w io.Writer, columns ...interface{}) {
+	items := make([]string, 0, len(columns))
+	for _, c := range columns {
+		items = append(items, strings.ReplaceAll(fmt.Sprint(c), "\n", " "))
+	}
+	fmt.Fprintln(w, strings.Join(items, "\t"))
+}
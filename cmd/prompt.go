@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gookit/color"
+	"github.com/pkg/errors"
+	"golang.org/x/term"
+)
+
+// prompter asks the questions and reads the answers line by line.
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// newPrompter returns a new prompter.
+func newPrompter(in io.Reader, out io.Writer) *prompter {
+	return &prompter{in: bufio.NewReader(in), out: out}
+}
+
+// ask asks the question, the default value will be returned if the answer is empty.
+func (p *prompter) ask(question, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", question)
+	}
+
+	answer, err := p.readLine()
+	if err != nil {
+		return "", err
+	}
+	if answer == "" {
+		return def, nil
+	}
+
+	return answer, nil
+}
+
+// askInt asks the question until the answer is an integer.
+func (p *prompter) askInt(question string, def int) (int, error) {
+	for {
+		answer, err := p.ask(question, strconv.Itoa(def))
+		if err != nil {
+			return 0, err
+		}
+		i, err := strconv.Atoi(answer)
+		if err == nil {
+			return i, nil
+		}
+		fmt.Fprintln(p.out, color.Yellow.Sprint("invalid integer:", answer))
+	}
+}
+
+// askPassword asks the password without echo if the input is a terminal.
+func (p *prompter) askPassword(question string) (string, error) {
+	fmt.Fprintf(p.out, "%s: ", question)
+
+	if fd := int(os.Stdin.Fd()); p.in.Buffered() == 0 && term.IsTerminal(fd) {
+		b, err := term.ReadPassword(fd)
+		fmt.Fprintln(p.out)
+		if err != nil {
+			return "", errors.WithMessage(err, "term.ReadPassword err")
+		}
+		return string(b), nil
+	}
+
+	return p.readLine()
+}
+
+// choose asks to choose one of the options by its number or name.
+func (p *prompter) choose(question string, options []string, def string) (string, error) {
+	p.printOptions(options, nil)
+
+	for {
+		answer, err := p.ask(question, def)
+		if err != nil {
+			return "", err
+		}
+		if option, ok := findOption(options, answer); ok {
+			return option, nil
+		}
+		fmt.Fprintln(p.out, color.Yellow.Sprint("invalid option:", answer))
+	}
+}
+
+// chooseMulti asks to choose some of the options by their comma separated numbers or names,
+// the default options will be returned if the answer is empty.
+func (p *prompter) chooseMulti(question string, options, defaults []string) ([]string, error) {
+	p.printOptions(options, defaults)
+
+	for {
+		answer, err := p.ask(question, strings.Join(defaults, ","))
+		if err != nil {
+			return nil, err
+		}
+
+		var chosen, invalid []string
+		for _, item := range strings.Split(answer, ",") {
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+			if option, ok := findOption(options, item); ok {
+				chosen = append(chosen, option)
+			} else {
+				invalid = append(invalid, item)
+			}
+		}
+		if len(invalid) == 0 {
+			return chosen, nil
+		}
+		fmt.Fprintln(p.out, color.Yellow.Sprint("invalid options:", strings.Join(invalid, ",")))
+	}
+}
+
+// printOptions prints the numbered options, the chosen options are marked by *.
+func (p *prompter) printOptions(options, chosen []string) {
+	for i, option := range options {
+		mark := " "
+		for _, c := range chosen {
+			if strings.EqualFold(c, option) {
+				mark = "*"
+				break
+			}
+		}
+		fmt.Fprintf(p.out, "  %s %2d) %s\n", mark, i+1, option)
+	}
+}
+
+// readLine reads the trimmed line of the answer.
+func (p *prompter) readLine() (string, error) {
+	line, err := p.in.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		return "", errors.New("no answer is read from the input")
+	}
+
+	return strings.TrimSpace(line), nil
+}
+
+// findOption finds the option by its number or case-insensitive name.
+func findOption(options []string, answer string) (string, bool) {
+	if i, err := strconv.Atoi(answer); err == nil {
+		if i >= 1 && i <= len(options) {
+			return options[i-1], true
+		}
+		return "", false
+	}
+
+	for _, option := range options {
+		if strings.EqualFold(option, answer) {
+			return option, true
+		}
+	}
+
+	return "", false
+}
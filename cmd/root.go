@@ -17,12 +17,14 @@ const (
 var rootCmd = &cobra.Command{
 	Use:   "grom",
 	Short: "Get golang model structure by mysql information schema",
-	Example: "  grom generate -n ./grom.json\n" +
+	Example: "  grom init\n" +
+		"  grom generate -n ./grom.json\n" +
 		"  grom convert -n ./grom.json",
 }
 
 // Execute executes the root command and its subcommands.
 func Execute() {
+	rootCmd.SetArgs(normalizePasswordArgs(os.Args[1:]))
 	if err := rootCmd.Execute(); err != nil {
 		color.Red.Println(err.Error())
 		os.Exit(codeFailure)
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/sliveryou/grom/util"
+)
+
+// generatedHeader the header of the files generated by grom run, which is recognized by go tools.
+const generatedHeader = "// Code generated by grom. DO NOT EDIT.\n\n"
+
+var (
+	manifestPath string
+	workers      int
+
+	// stdinPassword the password read from the standard input, which can be read only once and is shared by all targets.
+	stdinPassword *string
+)
+
+var runCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Convert all targets of the project manifest",
+	Long: "Convert all targets of the project manifest, each target inherits the shared defaults, " +
+		"add //go:generate grom run into the models package to refresh all models by go generate ./...",
+	Example: "  grom run\n" +
+		"  grom run -n ./models/grom.yaml\n" +
+		"  grom run --password-file -\n" +
+		"  grom run -w 4\n" +
+		"  grom run -v",
+	RunE: runFunc,
+}
+
+func init() {
+	runCmd.Flags().StringVarP(&manifestPath, "name", "n", "grom.yaml", "the name of the project manifest file")
+	runCmd.Flags().StringVarP(&password, "password", "p", "", "the password of mysql used by all targets, prompt it if the flag is used without value, such as -p or --password")
+	runCmd.Flags().Lookup("password").NoOptDefVal = passwordPrompt
+	runCmd.Flags().StringVar(&passwordFile, "password-file", "", "the file containing the password of mysql used by all targets, - means reading from stdin")
+	runCmd.Flags().IntVarP(&workers, "workers", "w", runtime.NumCPU(), "the number of tables converted concurrently for the targets of tables")
+	runCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "print the details of conversion, such as the tables not embedding the base model")
+	rootCmd.AddCommand(runCmd)
+}
+
+func runFunc(_ *cobra.Command, _ []string) error {
+	defer util.CloseDB()
+
+	manifest, err := util.LoadManifest(manifestPath)
+	if err != nil {
+		return errors.WithMessage(err, "util.LoadManifest err")
+	}
+
+	// read the password once since it is shared by all targets
+	switch {
+	case password == passwordPrompt:
+		if password, err = promptPassword(); err != nil {
+			return err
+		}
+	case passwordFile == "-":
+		if password, err = readStdinPassword(); err != nil {
+			return err
+		}
+	case passwordFile != "":
+		if password, err = util.ReadPasswordFile(passwordFile, os.Stdin); err != nil {
+			return errors.WithMessage(err, "util.ReadPasswordFile err")
+		}
+	}
+
+	for i, target := range manifest.Targets {
+		if err = runTarget(target); err != nil {
+			return errors.WithMessage(err, "target "+strconv.Itoa(i)+" err")
+		}
+	}
+
+	return nil
+}
+
+// runTarget converts the table of target into the output file,
+// or the tables matched by the patterns into the output directory.
+func runTarget(target *util.ManifestTarget) error {
+	config := target.Config
+	if err := util.ApplyEnvConfig(&config); err != nil {
+		return errors.WithMessage(err, "util.ApplyEnvConfig err")
+	}
+	if password != "" {
+		config.Password, config.PasswordFile = password, ""
+	}
+	config.Verbose = verbose
+	if config.Password == "" && config.PasswordFile == "-" {
+		p, err := readStdinPassword()
+		if err != nil {
+			return err
+		}
+		config.Password, config.PasswordFile = p, ""
+	}
+	if err := util.ApplyCredentials(&config.DBConfig, os.Stdin); err != nil {
+		return errors.WithMessage(err, "util.ApplyCredentials err")
+	}
+
+	if len(target.Tables) == 0 {
+		return runTable(config, target.Output)
+	}
+
+	if config.StructName != "" {
+		return errors.New("struct_name can not be used with tables")
+	}
+	tables, err := util.MatchTables(&config, target.Tables, nil)
+	if err != nil {
+		return errors.WithMessage(err, "util.MatchTables err")
+	}
+	if len(tables) == 0 {
+		return errors.New("no table is matched by the patterns")
+	}
+
+	// the matched tables exist, so the config is validated once by the first table
+	tc := config
+	tc.Table = tables[0]
+	if err = util.ValidateCmdConfig(&tc); err != nil {
+		return err
+	}
+
+	outputs, err := util.ConvertTables(config, tables, workers)
+	if err != nil {
+		return errors.WithMessage(err, "util.ConvertTables err")
+	}
+	for _, output := range outputs {
+		if err = writeOutput(filepath.Join(target.Output, output.Table+".go"), output.Output); err != nil {
+			return errors.WithMessage(err, "table "+output.Table+" err")
+		}
+	}
+
+	return nil
+}
+
+// readStdinPassword reads the password from the standard input once, the read password is reused by later calls.
+func readStdinPassword() (string, error) {
+	if stdinPassword == nil {
+		p, err := util.ReadPasswordFile("-", os.Stdin)
+		if err != nil {
+			return "", errors.WithMessage(err, "util.ReadPasswordFile err")
+		}
+		stdinPassword = &p
+	}
+
+	return *stdinPassword, nil
+}
+
+// runTable converts the table of config and writes the output file.
+func runTable(config util.CmdConfig, output string) error {
+	if err := util.ValidateCmdConfig(&config); err != nil {
+		return err
+	}
+
+	out, err := util.ConvertTable(config)
+	if err != nil {
+		return errors.WithMessage(err, "util.ConvertTable err")
+	}
+
+	return writeOutput(output, out)
+}
+
+// writeOutput writes the output file with the generated header.
+func writeOutput(output, out string) error {
+	if err := os.MkdirAll(filepath.Dir(output), 0o755); err != nil {
+		return errors.WithMessage(err, "os.MkdirAll err")
+	}
+	err := os.WriteFile(output, []byte(generatedHeader+out+"\n"), writeFilePerm)
+	if err != nil {
+		return errors.WithMessage(err, "os.WriteFile err")
+	}
+
+	fmt.Println("write output in:", output)
+
+	return nil
+}
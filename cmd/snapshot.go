@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/sliveryou/grom/util"
+)
+
+var (
+	snapshotFileName string
+	snapshotFormat   string
+	snapshotTables   []string
+	snapshotExcludes []string
+	snapshotViews    string
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Dump mysql table schemas into snapshot file",
+	Long: "Dump the columns, indexes, foreign keys and comments of the selected tables into a single snapshot file, " +
+		"which can be used by convert --from-snapshot to generate the model structures without connecting to mysql",
+	Example: "  grom snapshot -n ./grom.json -o grom.snapshot.json\n" +
+		"  grom snapshot -H localhost -u user -p -d database -t 'user_*,order' --exclude '*_bak' -o grom.snapshot.yaml\n" +
+		"  grom convert --from-snapshot grom.snapshot.json -t user -e JSON_TAG,GORM_V2_TAG",
+	RunE: snapshotFunc,
+}
+
+func init() {
+	addConfigFileFlags(snapshotCmd.Flags())
+	addDBFlags(snapshotCmd.Flags())
+	snapshotCmd.Flags().StringSliceVarP(&snapshotTables, "tables", "t", nil, "the patterns of the included tables, such as user_*,order (default all tables)")
+	snapshotCmd.Flags().StringSliceVar(&snapshotExcludes, "exclude", nil, "the patterns of the excluded tables, such as *_bak,tmp_*")
+	snapshotCmd.Flags().StringVar(&snapshotViews, "views", "", "whether the views are matched by the patterns, must in [include,exclude,only] (default include)")
+	snapshotCmd.Flags().StringVarP(&snapshotFileName, "output", "o", "grom.snapshot.json", "the name of the snapshot file")
+	snapshotCmd.Flags().StringVarP(&snapshotFormat, "format", "f", "", "the format of the snapshot file, must in [json,yaml], detected by the file extension by default")
+	rootCmd.AddCommand(snapshotCmd)
+}
+
+func snapshotFunc(_ *cobra.Command, _ []string) error {
+	defer util.CloseDB()
+
+	format := strings.ToLower(snapshotFormat)
+	if format == "" {
+		format = util.GetConfigFormat(snapshotFileName)
+	}
+	if format != util.ConfigFormatJSON && format != util.ConfigFormatYAML {
+		return errors.New("snapshot format must in [json,yaml], format: " + format)
+	}
+
+	config, err := getConnectionConfig()
+	if err != nil {
+		return errors.WithMessage(err, "getConnectionConfig err")
+	}
+	if snapshotViews != "" {
+		config.Views = snapshotViews
+	}
+	switch strings.ToLower(config.Views) {
+	case "", util.ViewsInclude, util.ViewsExclude, util.ViewsOnly:
+	default:
+		return errors.New("views must in [include,exclude,only], views: " + config.Views)
+	}
+
+	snapshot, err := util.GetSnapshot(config, snapshotTables, snapshotExcludes)
+	if err != nil {
+		return errors.WithMessage(err, "util.GetSnapshot err")
+	}
+
+	content, err := util.MarshalValue(snapshot, format)
+	if err != nil {
+		return errors.WithMessage(err, "util.MarshalValue err")
+	}
+	if format == util.ConfigFormatJSON {
+		content = append(content, '\n')
+	}
+
+	err = os.WriteFile(snapshotFileName, content, writeFilePerm)
+	if err != nil {
+		return errors.WithMessage(err, "os.WriteFile err")
+	}
+
+	fmt.Printf("write %d tables in: %s\n", len(snapshot.Tables), snapshotFileName)
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"github.com/gookit/color"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/sliveryou/grom/util"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate grom configuration",
+	Long: "Validate grom configuration file and flags, unknown keys, mutually exclusive services, " +
+		"invalid names and options, and the absence of the table will be reported at once",
+	Example: "  grom validate -n ./grom.json\n" +
+		"  grom validate -n ./grom.yaml --profile dev",
+	RunE: validateFunc,
+}
+
+func init() {
+	addConvertFlags(validateCmd.Flags())
+	rootCmd.AddCommand(validateCmd)
+}
+
+func validateFunc(_ *cobra.Command, _ []string) error {
+	defer util.CloseDB()
+
+	config, err := getCmdConfig()
+	if err != nil {
+		return errors.WithMessage(err, "getCmdConfig err")
+	}
+	if err = util.ValidateCmdConfig(config); err != nil {
+		return err
+	}
+
+	color.Green.Println("config is valid")
+
+	return nil
+}
@@ -3,8 +3,12 @@ module github.com/sliveryou/grom
 go 1.16
 
 require (
+	github.com/BurntSushi/toml v1.3.2
 	github.com/go-sql-driver/mysql v1.7.1
 	github.com/gookit/color v1.5.2
 	github.com/pkg/errors v0.9.1
 	github.com/spf13/cobra v1.8.0
+	github.com/spf13/pflag v1.0.5
+	golang.org/x/term v0.0.0-20210503060354-a79de5458b56
+	gopkg.in/yaml.v3 v3.0.1
 )
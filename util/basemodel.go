@@ -0,0 +1,117 @@
+package util
+
+import (
+	"fmt"
+	"go/token"
+	"sort"
+	"strings"
+)
+
+// builtinBaseModels maps the builtin base models to their column types.
+var builtinBaseModels = map[string]map[string]string{
+	"gorm.Model": {"id": GoUint, "created_at": GoTime, "updated_at": GoTime, "deleted_at": GormDeletedAt},
+}
+
+// integerTypeGroups groups the integer types by signedness, the types in the same group are compatible.
+var integerTypeGroups = [][]string{{GoInt, GoInt32, GoInt64}, {GoUint, GoUint32, GoUint64}}
+
+// getBaseModelColumns returns the column types of the base model, the builtin columns are used if not configured.
+func getBaseModelColumns(bc *BaseModelConfig) map[string]string {
+	if len(bc.Columns) > 0 {
+		return bc.Columns
+	}
+
+	return builtinBaseModels[bc.Name]
+}
+
+// isCompatibleType reports whether the field type is compatible with the type of base model field.
+func isCompatibleType(fieldType, baseType string) bool {
+	if fieldType == baseType {
+		return true
+	}
+	for _, group := range integerTypeGroups {
+		if containsString(group, fieldType) && containsString(group, baseType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// embedBaseModel replaces the fields of base model columns with the embedded base model, which is placed
+// at the first base model field. The fields are left untouched and the mismatch reason is returned if the
+// table does not contain all base model columns with the compatible types.
+func embedBaseModel(bc *BaseModelConfig, fields []*StructField) ([]*StructField, string) {
+	columns := getBaseModelColumns(bc)
+	names := make([]string, 0, len(columns))
+	for name := range columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fieldMap := make(map[string]*StructField, len(fields))
+	for _, field := range fields {
+		fieldMap[field.RawName] = field
+	}
+	for _, name := range names {
+		field, ok := fieldMap[name]
+		if !ok {
+			return fields, "missing column " + name
+		}
+		if !isCompatibleType(field.Type, columns[name]) {
+			return fields, fmt.Sprintf("column %s is %s instead of %s", name, field.Type, columns[name])
+		}
+	}
+
+	embedded := make([]*StructField, 0, len(fields)-len(columns)+1)
+	for _, field := range fields {
+		if _, ok := columns[field.RawName]; !ok {
+			embedded = append(embedded, field)
+		} else if !containsEmbeddedField(embedded) {
+			embedded = append(embedded, &StructField{Type: bc.Name, IsEmbedded: true})
+		}
+	}
+
+	return embedded, ""
+}
+
+// containsEmbeddedField reports whether the fields contain the embedded field.
+func containsEmbeddedField(fields []*StructField) bool {
+	for _, field := range fields {
+		if field.IsEmbedded {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getBaseModelProblems returns the problems of the base model config.
+func getBaseModelProblems(bc *BaseModelConfig) []string {
+	if bc.Name == "" {
+		return nil
+	}
+
+	var problems []string
+	pkg, name := splitQualifiedName(bc.Name)
+	if (pkg != "" && !token.IsIdentifier(pkg)) || !token.IsIdentifier(name) || !token.IsExported(name) {
+		problems = append(problems, "base_model.name is not a valid exported go type: "+bc.Name)
+	}
+	if len(getBaseModelColumns(bc)) == 0 {
+		problems = append(problems, "base_model.columns is required for "+bc.Name)
+	}
+	if _, ok := importPaths[pkg]; pkg != "" && !ok && bc.Import == "" {
+		problems = append(problems, "base_model.import is required for "+bc.Name)
+	}
+
+	return problems
+}
+
+// splitQualifiedName splits the qualified type name into the package name and the type name.
+func splitQualifiedName(name string) (string, string) {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+
+	return "", name
+}
@@ -0,0 +1,137 @@
+package util
+
+import (
+	"go/token"
+	"sort"
+	"strings"
+)
+
+// tableColumns represents the column rules of the table, the included columns are kept if not empty,
+// then the excluded columns are removed, and the renamed columns use the configured field names.
+type tableColumns struct {
+	include []string
+	exclude []string
+	rename  map[string]string
+}
+
+// splitTableColumn splits the table column in the form of table.column into the table and column names.
+func splitTableColumn(tableColumn string) (string, string, bool) {
+	i := strings.Index(tableColumn, ".")
+	if i <= 0 || i == len(tableColumn)-1 {
+		return "", "", false
+	}
+
+	return tableColumn[:i], tableColumn[i+1:], true
+}
+
+// getTableColumns returns the column rules of the table by the include_columns, exclude_columns
+// and rename_columns of the command config.
+func getTableColumns(cc *CmdConfig, table string) *tableColumns {
+	tc := &tableColumns{rename: make(map[string]string)}
+	for _, tableColumn := range cc.IncludeColumns {
+		if t, column, ok := splitTableColumn(tableColumn); ok && t == table {
+			tc.include = append(tc.include, column)
+		}
+	}
+	for _, tableColumn := range cc.ExcludeColumns {
+		if t, column, ok := splitTableColumn(tableColumn); ok && t == table {
+			tc.exclude = append(tc.exclude, column)
+		}
+	}
+	for tableColumn, name := range cc.RenameColumns {
+		if t, column, ok := splitTableColumn(tableColumn); ok && t == table {
+			tc.rename[column] = name
+		}
+	}
+
+	return tc
+}
+
+// filterColumns returns the columns filtered by the column rules of the table,
+// and the unknown columns referenced by the rules are warned.
+func filterColumns(table string, tc *tableColumns, cis []*ColumnInfo) []*ColumnInfo {
+	for _, column := range getUnknownColumns(tc, cis) {
+		printWarning("column %s of the column rules is not found in table %s", column, table)
+	}
+
+	filtered := make([]*ColumnInfo, 0, len(cis))
+	for _, ci := range cis {
+		if (len(tc.include) == 0 || containsString(tc.include, ci.Name)) && !containsString(tc.exclude, ci.Name) {
+			filtered = append(filtered, ci)
+		}
+	}
+
+	return filtered
+}
+
+// getUnknownColumns returns the sorted column names referenced by the column rules but not found in the columns.
+func getUnknownColumns(tc *tableColumns, cis []*ColumnInfo) []string {
+	columns := make(map[string]struct{}, len(cis))
+	for _, ci := range cis {
+		columns[ci.Name] = struct{}{}
+	}
+
+	referenced := make([]string, 0, len(tc.include)+len(tc.exclude)+len(tc.rename))
+	referenced = append(referenced, tc.include...)
+	referenced = append(referenced, tc.exclude...)
+	for column := range tc.rename {
+		referenced = append(referenced, column)
+	}
+
+	var unknown []string
+	for _, column := range referenced {
+		if _, ok := columns[column]; !ok && !containsString(unknown, column) {
+			unknown = append(unknown, column)
+		}
+	}
+	sort.Strings(unknown)
+
+	return unknown
+}
+
+// getFieldNames returns the field names keyed by the column names, the renamed columns use the configured names.
+func getFieldNames(cis []*ColumnInfo, rename map[string]string, initialisms map[string]string) map[string]string {
+	names := make(map[string]string, len(cis))
+	for _, ci := range cis {
+		if name, ok := rename[ci.Name]; ok {
+			names[ci.Name] = name
+		} else {
+			names[ci.Name] = convertName(ci.Name, initialisms)
+		}
+	}
+
+	return names
+}
+
+// getColumnRuleProblems returns the problems of the include_columns, exclude_columns and rename_columns.
+func getColumnRuleProblems(cc *CmdConfig) []string {
+	var problems []string
+	for _, rule := range []struct {
+		key     string
+		columns []string
+	}{
+		{"include_columns", cc.IncludeColumns}, {"exclude_columns", cc.ExcludeColumns},
+	} {
+		for _, tableColumn := range rule.columns {
+			if _, _, ok := splitTableColumn(tableColumn); !ok {
+				problems = append(problems, rule.key+" must be in the form of table.column: "+tableColumn)
+			}
+		}
+	}
+
+	renamed := make([]string, 0, len(cc.RenameColumns))
+	for tableColumn := range cc.RenameColumns {
+		renamed = append(renamed, tableColumn)
+	}
+	sort.Strings(renamed)
+	for _, tableColumn := range renamed {
+		name := cc.RenameColumns[tableColumn]
+		if _, _, ok := splitTableColumn(tableColumn); !ok {
+			problems = append(problems, "rename_columns must be in the form of table.column: "+tableColumn)
+		} else if !token.IsIdentifier(name) || !token.IsExported(name) {
+			problems = append(problems, "rename_columns."+tableColumn+" is not a valid exported go identifier: "+name)
+		}
+	}
+
+	return problems
+}
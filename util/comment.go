@@ -0,0 +1,80 @@
+package util
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// escapeTagComment escapes the comment for the tag syntax, the lines are joined by spaces, the single quotes
+// are doubled if the comment is quoted, and the characters which can not be escaped are replaced with spaces.
+func escapeTagComment(comment string, quoted bool, unsupported string) string {
+	comment = singleLine(comment)
+	if quoted {
+		comment = strings.ReplaceAll(comment, "'", "''")
+	}
+	if unsupported != "" && strings.ContainsAny(comment, unsupported) {
+		comment = strings.Join(strings.FieldsFunc(comment, func(r rune) bool {
+			return r == ' ' || strings.ContainsRune(unsupported, r)
+		}), " ")
+	}
+
+	return comment
+}
+
+// getGormComment returns the comment of gorm v1 tag, which is quoted and can not contain the semicolon.
+func getGormComment(comment string) string {
+	return escapeTagComment(comment, true, ";")
+}
+
+// getGormV2Comment returns the comment of gorm v2 tag, the semicolons are escaped by backslash.
+func getGormV2Comment(comment string) string {
+	return strings.ReplaceAll(singleLine(comment), ";", `\;`)
+}
+
+// getXormComment returns the comment of xorm tag, which is quoted and can not contain the comma.
+func getXormComment(comment string) string {
+	return escapeTagComment(comment, true, ",")
+}
+
+// getBeegoComment returns the description of beego orm tag, which is quoted by beego orm itself
+// and can not contain the semicolon and parenthesis.
+func getBeegoComment(comment string) string {
+	return escapeTagComment(comment, true, ";()")
+}
+
+// formatStructTag returns the struct tag literal of the tags, the interpreted string literal
+// is used if the tags contain the backquote which can not be written in the raw string literal.
+func formatStructTag(tags []string) string {
+	tag := strings.Join(tags, " ")
+	if strings.Contains(tag, "`") {
+		return strconv.Quote(tag)
+	}
+
+	return "`" + tag + "`"
+}
+
+// commentLines returns the non-empty lines of the comment, the control characters are replaced with spaces.
+func commentLines(comment string) []string {
+	comment = strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(comment)
+	comment = strings.Map(func(r rune) rune {
+		if r != '\n' && r != '\t' && (unicode.IsControl(r) || r == '\uFEFF') {
+			return ' '
+		}
+		return r
+	}, comment)
+
+	var lines []string
+	for _, line := range strings.Split(comment, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+// singleLine returns the comment lines joined by spaces.
+func singleLine(comment string) string {
+	return strings.Join(commentLines(comment), " ")
+}
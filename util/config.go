@@ -0,0 +1,559 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Config file format constants.
+const (
+	ConfigFormatJSON = "json"
+	ConfigFormatYAML = "yaml"
+	ConfigFormatTOML = "toml"
+)
+
+const (
+	// configProfilesKey the key of the named profiles in the config file.
+	configProfilesKey = "profiles"
+	// configEnvPrefix the prefix of the environment variables overriding the config.
+	configEnvPrefix = "GROM_"
+)
+
+// envVarRegexp matches the ${ENV_VAR} and ${ENV_VAR:-default} references.
+var envVarRegexp = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// configField represents the field of the config struct, the fields of embedded structs are flattened.
+type configField struct {
+	index []int
+	typ   reflect.Type
+}
+
+// GetConfigFormat returns the config file format detected by the file extension,
+// json is the default format.
+func GetConfigFormat(filePath string) string {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".yaml", ".yml":
+		return ConfigFormatYAML
+	case ".toml":
+		return ConfigFormatTOML
+	default:
+		return ConfigFormatJSON
+	}
+}
+
+// LoadCmdConfig loads the command config from the json, yaml or toml file, the ${ENV_VAR}
+// references in the values will be expanded, and the named profile will be merged into the base config.
+func LoadCmdConfig(filePath, profile string) (*CmdConfig, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, errors.WithMessage(err, "os.ReadFile err")
+	}
+
+	values, err := unmarshalConfigValues(content, GetConfigFormat(filePath))
+	if err != nil {
+		return nil, err
+	}
+
+	unknownKeys := getUnknownConfigKeys(values)
+
+	values, err = mergeConfigProfile(values, profile)
+	if err != nil {
+		return nil, err
+	}
+	values = expandConfigValues(values, getConfigFields()).(map[string]interface{})
+
+	b, err := json.Marshal(values)
+	if err != nil {
+		return nil, errors.WithMessage(err, "json.Marshal err")
+	}
+
+	config := CmdConfig{unknownKeys: unknownKeys}
+	if err = json.Unmarshal(b, &config); err != nil {
+		return nil, errors.WithMessage(err, "json.Unmarshal err")
+	}
+
+	return &config, nil
+}
+
+// ValidateCmdConfig validates the command config, the unknown keys of the loaded config file,
+// mutually exclusive services, invalid names and options, and the absence of the table in mysql,
+// the schema file or the snapshot file will be reported by the *ConfigError at once. Note that after using this function, you need to call
+// the util.CloseDB() function to close the database.
+func ValidateCmdConfig(cc *CmdConfig) error {
+	problems := getConfigProblems(cc)
+
+	if len(problems) == 0 && cc.SchemaFile == "" && cc.SnapshotFile == "" {
+		tables, err := GetTables(cc)
+		if err != nil {
+			problems = append(problems, "failed to query the tables of mysql: "+err.Error())
+		} else if !containsString(tables, cc.Table) {
+			problems = append(problems, "table is not found in database "+cc.Database+": "+cc.Table)
+		}
+	}
+
+	if len(problems) != 0 {
+		return &ConfigError{Problems: problems}
+	}
+
+	return nil
+}
+
+// Error returns the problems of the config line by line.
+func (e *ConfigError) Error() string {
+	return "invalid config:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// getConfigProblems returns the problems of the command config without connecting to the database.
+func getConfigProblems(cc *CmdConfig) []string {
+	var problems []string
+
+	for _, key := range cc.unknownKeys {
+		problems = append(problems, "unknown key: "+key)
+	}
+
+	if cc.EnableGormTag && cc.EnableGormV2Tag {
+		problems = append(problems, "GORM_TAG (enable_gorm_tag) and GORM_V2_TAG (enable_gorm_v2_tag) are mutually exclusive")
+	}
+	if cc.EnableGureguNull && cc.EnableSQLNull {
+		problems = append(problems, "GUREGU_NULL (enable_guregu_null) and SQL_NULL (enable_sql_null) are mutually exclusive")
+	}
+
+	if cc.PackageName != "" && !token.IsIdentifier(cc.PackageName) {
+		problems = append(problems, "package name is not a valid go identifier: "+cc.PackageName)
+	}
+	if cc.StructName != "" && !token.IsIdentifier(cc.StructName) {
+		problems = append(problems, "struct name is not a valid go identifier: "+cc.StructName)
+	}
+
+	if cc.SchemaFile != "" && cc.SnapshotFile != "" {
+		problems = append(problems, "schema file and snapshot file are mutually exclusive")
+	}
+
+	if cc.SnapshotFile != "" {
+		if snapshot, err := LoadSnapshot(cc.SnapshotFile); err != nil {
+			problems = append(problems, "invalid snapshot file: "+err.Error())
+		} else if cc.Table == "" {
+			problems = append(problems, "table is required")
+		} else if findTableSchema(snapshot, cc.Table) == nil {
+			problems = append(problems, "table is not found in snapshot file: "+cc.Table)
+		}
+	} else if cc.SchemaFile != "" {
+		if schema, err := LoadSchema(cc.SchemaFile); err != nil {
+			problems = append(problems, "invalid schema file: "+err.Error())
+		} else if cc.Table != "" && cc.Table != schema.Table {
+			problems = append(problems, "table is not found in schema file: "+cc.Table)
+		}
+	} else {
+		dc := cc.DBConfig
+		if _, err := getMySQLConfig(&dc); err != nil {
+			problems = append(problems, "invalid connection: "+err.Error())
+		} else if dc.Database == "" {
+			problems = append(problems, "database is required")
+		}
+		if cc.Table == "" {
+			problems = append(problems, "table is required")
+		}
+	}
+
+	for _, tag := range []struct {
+		key string
+		tc  TagConfig
+	}{
+		{"json_tag", cc.JSONTag}, {"xml_tag", cc.XMLTag}, {"yaml_tag", cc.YAMLTag}, {"toml_tag", cc.TOMLTag},
+		{"bson_tag", cc.BSONTag}, {"msgpack_tag", cc.MsgpackTag}, {"mapstructure_tag", cc.MapstructureTag}, {"form_tag", cc.FormTag},
+	} {
+		switch strings.ToLower(tag.tc.Naming) {
+		case "", NamingRaw, NamingSnake, NamingCamel, NamingPascal, NamingKebab:
+		default:
+			problems = append(problems, tag.key+".naming must in [raw,snake,camel,pascal,kebab]: "+tag.tc.Naming)
+		}
+	}
+
+	switch strings.ToLower(cc.Views) {
+	case "", ViewsInclude, ViewsExclude, ViewsOnly:
+	default:
+		problems = append(problems, "views must in [include,exclude,only]: "+cc.Views)
+	}
+
+	for _, rule := range cc.ValidateTag.DisabledRules {
+		switch rule {
+		case ValidateRuleRequired, ValidateRuleLength, ValidateRuleUnsigned, ValidateRuleEnum, ValidateRuleRange, ValidateRuleCheck:
+		default:
+			problems = append(problems, "validate_tag.disabled_rules must in [required,length,unsigned,enum,range,check]: "+rule)
+		}
+	}
+
+	rules := make([]string, 0, len(cc.Conventions.DisabledRules)+len(cc.Conventions.Columns))
+	rules = append(rules, cc.Conventions.DisabledRules...)
+	for rule := range cc.Conventions.Columns {
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules[len(cc.Conventions.DisabledRules):])
+	for _, rule := range rules {
+		if !containsString(conventionRules, rule) {
+			problems = append(problems, "conventions rules must in [created_at,updated_at,deleted_at,version,is_deleted]: "+rule)
+		}
+	}
+
+	problems = append(problems, getBaseModelProblems(&cc.BaseModel)...)
+	problems = append(problems, getColumnRuleProblems(cc)...)
+
+	if _, err := parseCustomTags(cc.CustomTags); err != nil {
+		problems = append(problems, "invalid custom tags: "+err.Error())
+	}
+
+	return problems
+}
+
+// ApplyEnvConfig overrides the command config by the GROM_* environment variables,
+// such as GROM_HOST, GROM_PASSWORD and GROM_ENABLE_JSON_TAG.
+func ApplyEnvConfig(cc *CmdConfig) error {
+	v := reflect.ValueOf(cc).Elem()
+	for name, field := range getConfigFields() {
+		env := configEnvPrefix + strings.ToUpper(name)
+		value, ok := os.LookupEnv(env)
+		if !ok {
+			continue
+		}
+
+		fv := v.FieldByIndex(field.index)
+		switch field.typ.Kind() {
+		case reflect.String:
+			fv.SetString(value)
+		case reflect.Int:
+			i, err := strconv.Atoi(value)
+			if err != nil {
+				return errors.Errorf("invalid environment variable %s: %s", env, value)
+			}
+			fv.SetInt(int64(i))
+		case reflect.Bool:
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return errors.Errorf("invalid environment variable %s: %s", env, value)
+			}
+			fv.SetBool(b)
+		case reflect.Slice:
+			if fv.Type().Elem().Kind() == reflect.String {
+				fv.Set(reflect.ValueOf(splitEnvList(value)))
+			}
+		default:
+			// nested configs can not be overridden by environment variables
+		}
+	}
+
+	return nil
+}
+
+// MarshalCmdConfig marshals the command config to json, yaml or toml.
+func MarshalCmdConfig(cc *CmdConfig, format string) ([]byte, error) {
+	return MarshalValue(cc, format)
+}
+
+// MarshalValue marshals the value to json, yaml or toml by its json tags,
+// the order of the struct fields is kept in json and yaml.
+func MarshalValue(v interface{}, format string) ([]byte, error) {
+	b, err := json.MarshalIndent(v, "", "    ")
+	if err != nil {
+		return nil, errors.WithMessage(err, "json.MarshalIndent err")
+	}
+
+	switch format {
+	case ConfigFormatJSON:
+		return b, nil
+	case ConfigFormatYAML:
+		// decode json into yaml node to keep the order of keys
+		var node yaml.Node
+		if err = yaml.Unmarshal(b, &node); err != nil {
+			return nil, errors.WithMessage(err, "yaml.Unmarshal err")
+		}
+		resetYAMLStyle(&node)
+
+		buffer := &bytes.Buffer{}
+		encoder := yaml.NewEncoder(buffer)
+		encoder.SetIndent(2)
+		if err = encoder.Encode(&node); err != nil {
+			return nil, errors.WithMessage(err, "yaml.Encode err")
+		}
+		return buffer.Bytes(), nil
+	case ConfigFormatTOML:
+		values, err := unmarshalConfigValues(b, ConfigFormatJSON)
+		if err != nil {
+			return nil, err
+		}
+
+		buffer := &bytes.Buffer{}
+		if err = toml.NewEncoder(buffer).Encode(values); err != nil {
+			return nil, errors.WithMessage(err, "toml.Encode err")
+		}
+		return buffer.Bytes(), nil
+	default:
+		return nil, errors.New("invalid format: " + format)
+	}
+}
+
+// unmarshalConfigValues unmarshals the content of config file into values map.
+func unmarshalConfigValues(content []byte, format string) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+
+	switch format {
+	case ConfigFormatYAML:
+		if err := yaml.Unmarshal(content, &values); err != nil {
+			return nil, errors.WithMessage(err, "yaml.Unmarshal err")
+		}
+	case ConfigFormatTOML:
+		if err := toml.Unmarshal(content, &values); err != nil {
+			return nil, errors.WithMessage(err, "toml.Unmarshal err")
+		}
+	default:
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.UseNumber()
+		if err := decoder.Decode(&values); err != nil {
+			return nil, errors.WithMessage(err, "json.Decode err")
+		}
+		values = convertJSONNumbers(values).(map[string]interface{})
+	}
+
+	return values, nil
+}
+
+// mergeConfigProfile merges the named profile into the base config values,
+// the profiles will be removed from the values.
+func mergeConfigProfile(values map[string]interface{}, profile string) (map[string]interface{}, error) {
+	profiles, _ := values[configProfilesKey].(map[string]interface{})
+	delete(values, configProfilesKey)
+
+	if profile == "" {
+		return values, nil
+	}
+
+	profileValues, ok := profiles[profile].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("config profile is not found, profile: " + profile)
+	}
+
+	return mergeConfigValues(values, profileValues), nil
+}
+
+// mergeConfigValues merges the override values into the base values recursively.
+func mergeConfigValues(base, override map[string]interface{}) map[string]interface{} {
+	for key, value := range override {
+		baseMap, isBaseMap := base[key].(map[string]interface{})
+		overrideMap, isOverrideMap := value.(map[string]interface{})
+		if isBaseMap && isOverrideMap {
+			base[key] = mergeConfigValues(baseMap, overrideMap)
+		} else {
+			base[key] = value
+		}
+	}
+
+	return base
+}
+
+// expandConfigValues expands the ${ENV_VAR} references in the string values recursively,
+// the expanded top level values of int and bool fields will be converted to their types.
+func expandConfigValues(value interface{}, fields map[string]configField) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			expanded := expandConfigValues(item, nil)
+			if s, ok := expanded.(string); ok && s != item {
+				// the nested and unknown keys have no config fields
+				if f, ok := fields[key]; ok && f.typ != nil {
+					expanded = convertConfigValue(s, f.typ.Kind())
+				}
+			}
+			v[key] = expanded
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = expandConfigValues(item, nil)
+		}
+		return v
+	case string:
+		return expandEnv(v)
+	default:
+		return v
+	}
+}
+
+// convertConfigValue converts the string value to the kind of config field if possible.
+func convertConfigValue(value string, kind reflect.Kind) interface{} {
+	switch kind {
+	case reflect.Int:
+		if i, err := strconv.Atoi(value); err == nil {
+			return i
+		}
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	default:
+	}
+
+	return value
+}
+
+// expandEnv expands the ${ENV_VAR} and ${ENV_VAR:-default} references in the string,
+// the $ENV_VAR form is not expanded since it may be part of the password.
+func expandEnv(s string) string {
+	return envVarRegexp.ReplaceAllStringFunc(s, func(ref string) string {
+		match := envVarRegexp.FindStringSubmatch(ref)
+		if value, ok := os.LookupEnv(match[1]); ok && (value != "" || match[2] == "") {
+			return value
+		}
+		return match[3]
+	})
+}
+
+// convertJSONNumbers converts the json numbers to int64 or float64 recursively.
+func convertJSONNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = convertJSONNumbers(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = convertJSONNumbers(item)
+		}
+		return v
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	default:
+		return v
+	}
+}
+
+// resetYAMLStyle resets the style of yaml nodes decoded from json to the default block style.
+func resetYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, n := range node.Content {
+		resetYAMLStyle(n)
+	}
+}
+
+// getConfigFields returns the top level fields of CmdConfig keyed by the json name.
+func getConfigFields() map[string]configField {
+	return getTagFields(reflect.TypeOf(CmdConfig{}), "json")
+}
+
+// getTagFields returns the fields of the struct keyed by the name of the tag key,
+// the fields of embedded structs are flattened.
+func getTagFields(t reflect.Type, key string) map[string]configField {
+	fields := make(map[string]configField)
+
+	var walk func(t reflect.Type, index []int)
+	walk = func(t reflect.Type, index []int) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			fieldIndex := append(append([]int{}, index...), i)
+			if f.Anonymous && f.Type.Kind() == reflect.Struct {
+				walk(f.Type, fieldIndex)
+				continue
+			}
+
+			name := strings.Split(f.Tag.Get(key), ",")[0]
+			if f.PkgPath != "" || name == "" || name == "-" {
+				continue
+			}
+			fields[name] = configField{index: fieldIndex, typ: f.Type}
+		}
+	}
+	walk(t, nil)
+
+	return fields
+}
+
+// getUnknownConfigKeys returns the sorted keys of config values which are unknown to CmdConfig,
+// the keys of named profiles are checked as well.
+func getUnknownConfigKeys(values map[string]interface{}) []string {
+	cmdConfigType := reflect.TypeOf(CmdConfig{})
+
+	base := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		if key != configProfilesKey {
+			base[key] = value
+		}
+	}
+	unknownKeys := collectUnknownKeys(base, cmdConfigType, "")
+
+	if value, ok := values[configProfilesKey]; ok {
+		profiles, ok := value.(map[string]interface{})
+		if !ok {
+			unknownKeys = append(unknownKeys, configProfilesKey)
+		}
+		for name, profile := range profiles {
+			profileValues, _ := profile.(map[string]interface{})
+			unknownKeys = append(unknownKeys,
+				collectUnknownKeys(profileValues, cmdConfigType, configProfilesKey+"."+name+".")...)
+		}
+	}
+	sort.Strings(unknownKeys)
+
+	return unknownKeys
+}
+
+// collectUnknownKeys collects the keys of values which are not the json names of the struct fields recursively.
+func collectUnknownKeys(values map[string]interface{}, t reflect.Type, prefix string) []string {
+	fields := getTagFields(t, "json")
+
+	var unknownKeys []string
+	for key, value := range values {
+		field, ok := fields[key]
+		if !ok {
+			unknownKeys = append(unknownKeys, prefix+key)
+			continue
+		}
+
+		ft := field.typ
+		switch ft.Kind() {
+		case reflect.Struct:
+			if m, ok := value.(map[string]interface{}); ok {
+				unknownKeys = append(unknownKeys, collectUnknownKeys(m, ft, prefix+key+".")...)
+			}
+		case reflect.Slice:
+			items, _ := value.([]interface{})
+			if ft.Elem().Kind() != reflect.Struct {
+				continue
+			}
+			for i, item := range items {
+				if m, ok := item.(map[string]interface{}); ok {
+					unknownKeys = append(unknownKeys, collectUnknownKeys(m, ft.Elem(), prefix+key+"["+strconv.Itoa(i)+"].")...)
+				}
+			}
+		default:
+		}
+	}
+
+	return unknownKeys
+}
+
+// splitEnvList splits the comma separated environment variable value.
+func splitEnvList(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,109 @@
+package util
+
+// conventionRules lists the convention rules in the matching order.
+var conventionRules = []string{
+	ConventionCreatedAt, ConventionUpdatedAt, ConventionDeletedAt, ConventionVersion, ConventionIsDeleted,
+}
+
+// getConventionColumns returns the column names of the convention rule, which are the rule name by default.
+func getConventionColumns(cc *ConventionConfig, rule string) []string {
+	if columns, ok := cc.Columns[rule]; ok {
+		return columns
+	}
+
+	return []string{rule}
+}
+
+// isConventionSupported reports whether the type of column is supported by the convention rule.
+func isConventionSupported(ci *ColumnInfo, rule string) bool {
+	switch rule {
+	case ConventionCreatedAt, ConventionUpdatedAt:
+		return isTimeColumn(ci) || isIntegerColumn(ci)
+	case ConventionDeletedAt:
+		return isTimeColumn(ci) && ci.IsNullable
+	case ConventionVersion, ConventionIsDeleted:
+		return isIntegerColumn(ci) && !ci.IsNullable
+	}
+
+	return false
+}
+
+// markConventionColumns marks the columns matched by the enabled convention rules, the columns whose types
+// are not supported by the rules are warned and left untouched, and each rule matches one column at most.
+// The is_deleted flag refers to the field of deleted_at column to record the deleted time if both exist.
+func markConventionColumns(cc *ConventionConfig, cis []*ColumnInfo, names map[string]string) {
+	matched := make(map[string]bool)
+	for _, ci := range cis {
+		ci.Convention, ci.DeletedAtField = "", ""
+		if ci.IsPrimaryKey || ci.IsReadOnly {
+			continue
+		}
+
+		for _, rule := range conventionRules {
+			if matched[rule] || containsString(cc.DisabledRules, rule) ||
+				!containsString(getConventionColumns(cc, rule), ci.Name) {
+				continue
+			}
+			if !isConventionSupported(ci, rule) {
+				printWarning("column %s matches the %s convention, but its type %s is not supported",
+					ci.Name, rule, ci.Type)
+				continue
+			}
+			ci.Convention, matched[rule] = rule, true
+			break
+		}
+	}
+
+	if ci := findConventionColumn(cis, ConventionIsDeleted); ci != nil {
+		if deletedAt := findConventionColumn(cis, ConventionDeletedAt); deletedAt != nil {
+			ci.DeletedAtField = names[deletedAt.Name]
+		}
+	}
+}
+
+// getConventionType returns the field type of the convention column in gorm v2, such as gorm.DeletedAt,
+// or empty string if the field type is not changed. The is_deleted flag is preferred if both soft delete
+// columns exist, and the deleted_at column keeps its type to record the deleted time.
+func getConventionType(ci *ColumnInfo, cis []*ColumnInfo) string {
+	switch ci.Convention {
+	case ConventionDeletedAt:
+		if findConventionColumn(cis, ConventionIsDeleted) == nil {
+			return GormDeletedAt
+		}
+	case ConventionIsDeleted:
+		return SoftDeleteDeletedAt
+	}
+
+	return ""
+}
+
+// findConventionColumn returns the column matched by the convention rule, or nil if not found.
+func findConventionColumn(cis []*ColumnInfo, rule string) *ColumnInfo {
+	for _, ci := range cis {
+		if ci.Convention == rule {
+			return ci
+		}
+	}
+
+	return nil
+}
+
+// isTimeColumn reports whether the column is the date or time column.
+func isTimeColumn(ci *ColumnInfo) bool {
+	switch ci.DataType {
+	case "date", "datetime", "timestamp":
+		return true
+	}
+
+	return false
+}
+
+// isIntegerColumn reports whether the column is the integer column.
+func isIntegerColumn(ci *ColumnInfo) bool {
+	switch ci.DataType {
+	case "tinyint", "smallint", "mediumint", "int", "integer", "bigint":
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,263 @@
+package util
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// myCnfFileName the default mysql option file name in the home directory.
+	myCnfFileName = ".my.cnf"
+	// myLoginFileName the default mysql login path file name in the home directory.
+	myLoginFileName = ".mylogin.cnf"
+	// myCnfClientSection the client section of the mysql option file.
+	myCnfClientSection = "client"
+	// stdinFileName the file name represents the standard input.
+	stdinFileName = "-"
+)
+
+// ApplyCredentials fills the empty connection fields of db config by the password file,
+// the login path of ~/.mylogin.cnf and the [client] section of the defaults file (~/.my.cnf by default).
+// The password file "-" means reading the password from the standard input. Only the user and password
+// are filled if the dsn is used, which are applied when the dsn does not contain them.
+func ApplyCredentials(dc *DBConfig, stdin io.Reader) error {
+	if dc.Password == "" && dc.PasswordFile != "" {
+		password, err := ReadPasswordFile(dc.PasswordFile, stdin)
+		if err != nil {
+			return errors.WithMessage(err, "ReadPasswordFile err")
+		}
+		dc.Password = password
+	}
+
+	if dc.DSN != "" {
+		// the other connection fields are given by the dsn
+		credentials := &DBConfig{
+			User: dc.User, Password: dc.Password, DefaultsFile: dc.DefaultsFile, LoginPath: dc.LoginPath,
+		}
+		if err := applyOptionFiles(credentials); err != nil {
+			return err
+		}
+		dc.User, dc.Password = credentials.User, credentials.Password
+		return nil
+	}
+
+	return applyOptionFiles(dc)
+}
+
+// applyOptionFiles fills the empty connection fields of db config by the login path of ~/.mylogin.cnf
+// and the [client] section of the defaults file (~/.my.cnf by default).
+func applyOptionFiles(dc *DBConfig) error {
+	if dc.LoginPath != "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return errors.WithMessage(err, "os.UserHomeDir err")
+		}
+
+		options, err := readMyLoginFile(filepath.Join(home, myLoginFileName), dc.LoginPath)
+		if err != nil {
+			return errors.WithMessage(err, "readMyLoginFile err")
+		}
+		if err = applyMyCnfOptions(dc, options); err != nil {
+			return err
+		}
+	}
+
+	defaultsFile := dc.DefaultsFile
+	if defaultsFile == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil
+		}
+		defaultsFile = filepath.Join(home, myCnfFileName)
+		if _, err = os.Stat(defaultsFile); err != nil {
+			// the default ~/.my.cnf is optional
+			return nil
+		}
+	}
+
+	f, err := os.Open(defaultsFile)
+	if err != nil {
+		return errors.WithMessage(err, "os.Open err")
+	}
+	defer f.Close()
+
+	options, err := parseMyCnf(f, myCnfClientSection)
+	if err != nil {
+		return errors.WithMessage(err, "parseMyCnf err")
+	}
+
+	return applyMyCnfOptions(dc, options)
+}
+
+// applyMyCnfOptions fills the empty connection fields of db config by the mysql options.
+func applyMyCnfOptions(dc *DBConfig, options map[string]string) error {
+	if v, ok := options["host"]; ok && dc.Host == "" {
+		dc.Host = v
+	}
+	if v, ok := options["port"]; ok && dc.Port == 0 {
+		port, err := strconv.Atoi(v)
+		if err != nil {
+			return errors.New("invalid port in mysql option file: " + v)
+		}
+		dc.Port = port
+	}
+	if v, ok := options["socket"]; ok && dc.Socket == "" {
+		dc.Socket = v
+	}
+	if v, ok := options["user"]; ok && dc.User == "" {
+		dc.User = v
+	}
+	if v, ok := options["password"]; ok && dc.Password == "" {
+		dc.Password = v
+	}
+	if v, ok := options["database"]; ok && dc.Database == "" {
+		dc.Database = v
+	}
+	if v, ok := options["default_character_set"]; ok && dc.Charset == "" {
+		dc.Charset = v
+	}
+
+	return nil
+}
+
+// parseMyCnf parses the options of the section in the mysql option file,
+// the dashes in the option names will be replaced by underscores.
+func parseMyCnf(r io.Reader, section string) (map[string]string, error) {
+	options := make(map[string]string)
+	current := ""
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' || line[0] == '!' {
+			continue
+		}
+		if line[0] == '[' && line[len(line)-1] == ']' {
+			current = strings.ToLower(strings.TrimSpace(line[1 : len(line)-1]))
+			continue
+		}
+		if current != section {
+			continue
+		}
+
+		key, value := line, ""
+		if i := strings.Index(line, "="); i >= 0 {
+			key, value = strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+		}
+		key = strings.ReplaceAll(strings.ToLower(key), "-", "_")
+		options[key] = unquoteMyCnfValue(value)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.WithMessage(err, "scanner.Scan err")
+	}
+
+	return options, nil
+}
+
+// unquoteMyCnfValue removes the quotes or the trailing comment of the mysql option value.
+func unquoteMyCnfValue(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
+		if end := strings.IndexByte(value[1:], value[0]); end >= 0 {
+			return value[1 : end+1]
+		}
+	}
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+
+	return value
+}
+
+// readMyLoginFile reads the options of the login path in the obfuscated mysql login path file,
+// which is generated by mysql_config_editor.
+func readMyLoginFile(path, loginPath string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithMessage(err, "os.ReadFile err")
+	}
+
+	plain, err := decryptMyLogin(content)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := parseMyCnf(bytes.NewReader(plain), strings.ToLower(loginPath))
+	if err != nil {
+		return nil, err
+	}
+	if len(options) == 0 {
+		return nil, errors.New("login path is not found, login path: " + loginPath)
+	}
+
+	return options, nil
+}
+
+// decryptMyLogin decrypts the content of mysql login path file, the file starts with 4 unused bytes
+// and 20 key bytes, followed by the aes-128-ecb encrypted lines prefixed by their 4 bytes length.
+func decryptMyLogin(content []byte) ([]byte, error) {
+	const unusedLen, keyLen, aesKeyLen = 4, 20, 16
+	if len(content) < unusedLen+keyLen {
+		return nil, errors.New("invalid mysql login path file")
+	}
+
+	key := make([]byte, aesKeyLen)
+	for i, b := range content[unusedLen : unusedLen+keyLen] {
+		key[i%aesKeyLen] ^= b
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.WithMessage(err, "aes.NewCipher err")
+	}
+
+	plain := &bytes.Buffer{}
+	data := content[unusedLen+keyLen:]
+	for len(data) >= 4 {
+		size := int(binary.LittleEndian.Uint32(data[:4]))
+		data = data[4:]
+		if size <= 0 || size > len(data) || size%block.BlockSize() != 0 {
+			return nil, errors.New("invalid mysql login path file")
+		}
+
+		line := make([]byte, size)
+		for i := 0; i < size; i += block.BlockSize() {
+			block.Decrypt(line[i:i+block.BlockSize()], data[i:i+block.BlockSize()])
+		}
+		// remove the pkcs7 padding
+		if padding := int(line[size-1]); padding > 0 && padding <= block.BlockSize() {
+			line = line[:size-padding]
+		}
+		plain.Write(line)
+		data = data[size:]
+	}
+
+	return plain.Bytes(), nil
+}
+
+// ReadPasswordFile reads the password from the first line of the file, "-" means the standard input.
+func ReadPasswordFile(path string, stdin io.Reader) (string, error) {
+	r := stdin
+	if path != stdinFileName {
+		f, err := os.Open(path)
+		if err != nil {
+			return "", errors.WithMessage(err, "os.Open err")
+		}
+		defer f.Close()
+		r = f
+	}
+
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", errors.WithMessage(err, "ReadString err")
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -1,11 +1,19 @@
 package util
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"database/sql"
 	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 	"github.com/gookit/color"
 	"github.com/pkg/errors"
 )
@@ -31,97 +39,523 @@ func closeRows(rows *sql.Rows) {
 	}
 }
 
-// getDB returns the opened db connection.
+// getDB returns the opened db connection, which is reused by the configs with the same connection settings,
+// and the connection of the different settings will be closed and opened again.
 func getDB(c *CmdConfig) (*sql.DB, error) {
 	dbMutex.Lock()
 	defer dbMutex.Unlock()
 
+	cfg, err := getMySQLConfig(&c.DBConfig)
+	if err != nil {
+		return nil, errors.WithMessage(err, "getMySQLConfig err")
+	}
+	key := strings.Join([]string{cfg.FormatDSN(), c.TLSCA, c.TLSCert, c.TLSKey}, "\n")
 	if db != nil {
-		return db, nil
+		if dbKey == key {
+			return db, nil
+		}
+		if err = db.Close(); err != nil {
+			color.Red.Println("db.Close err:", err)
+		}
+		db = nil
 	}
 
-	var err error
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8&parseTime=True&loc=Local",
-		c.User, c.Password, c.Host, c.Port, c.Database)
-
-	db, err = sql.Open(MySQLDriverName, dsn)
+	connector, err := mysql.NewConnector(cfg)
 	if err != nil {
-		return nil, errors.WithMessage(err, "sql.Open err")
+		return nil, errors.WithMessage(err, "mysql.NewConnector err")
 	}
+	db, dbKey = sql.OpenDB(connector), key
 
 	return db, nil
 }
 
-// getTableComment returns the comment of table.
-func getTableComment(c *CmdConfig) (string, error) {
+// getMySQLConfig returns the mysql driver config, the dsn will be parsed if it is not empty
+// and its missing user and password will be filled by the db config,
+// otherwise the config will be built by the host, port, socket, user, password and database,
+// and the charset, params and tls options will be applied to both of them.
+// Note that the database of db config will be filled by the dsn if it is empty.
+func getMySQLConfig(c *DBConfig) (*mysql.Config, error) {
+	var cfg *mysql.Config
+
+	if c.DSN != "" {
+		var err error
+		cfg, err = mysql.ParseDSN(c.DSN)
+		if err != nil {
+			return nil, errors.WithMessage(err, "mysql.ParseDSN err")
+		}
+		if cfg.User == "" {
+			cfg.User = c.User
+		}
+		if cfg.Passwd == "" {
+			cfg.Passwd = c.Password
+		}
+		if c.Database == "" {
+			c.Database = cfg.DBName
+		}
+	} else {
+		cfg = mysql.NewConfig()
+		cfg.User = c.User
+		cfg.Passwd = c.Password
+		if c.Socket != "" {
+			cfg.Net, cfg.Addr = "unix", c.Socket
+		} else {
+			port := c.Port
+			if port == 0 {
+				port = defaultMySQLPort
+			}
+			cfg.Net, cfg.Addr = "tcp", net.JoinHostPort(c.Host, strconv.Itoa(port))
+		}
+		cfg.ParseTime = true
+		cfg.Loc = time.Local
+		cfg.Params = map[string]string{"charset": defaultMySQLCharset}
+	}
+
+	if c.Database != "" {
+		cfg.DBName = c.Database
+	}
+	if c.Charset != "" {
+		if cfg.Params == nil {
+			cfg.Params = make(map[string]string)
+		}
+		cfg.Params["charset"] = c.Charset
+	}
+
+	tlsMode := strings.ToLower(c.TLS)
+	switch tlsMode {
+	case "", "false", "true", "skip-verify", "preferred":
+		if tlsMode != "" {
+			cfg.TLSConfig = tlsMode
+		}
+	default:
+		return nil, errors.New("invalid tls mode: " + c.TLS)
+	}
+
+	if len(c.Params) != 0 {
+		// format and parse the dsn again to make sure the params are parsed by the driver
+		keys := make([]string, 0, len(c.Params))
+		for key := range c.Params {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		params := make([]string, 0, len(keys))
+		for _, key := range keys {
+			params = append(params, key+"="+url.QueryEscape(c.Params[key]))
+		}
+
+		dsn, sep := cfg.FormatDSN(), "?"
+		if strings.Contains(dsn[strings.LastIndex(dsn, "/"):], "?") {
+			sep = "&"
+		}
+
+		var err error
+		cfg, err = mysql.ParseDSN(dsn + sep + strings.Join(params, "&"))
+		if err != nil {
+			return nil, errors.WithMessage(err, "mysql.ParseDSN err")
+		}
+	}
+
+	if c.TLSCA != "" || c.TLSCert != "" || c.TLSKey != "" {
+		if tlsMode == "false" {
+			return nil, errors.New("tls files can not be used with tls mode false")
+		}
+
+		tlsConfig, err := getTLSConfig(c)
+		if err != nil {
+			return nil, errors.WithMessage(err, "getTLSConfig err")
+		}
+		tlsConfig.InsecureSkipVerify = tlsMode == "skip-verify"
+		cfg.TLS = tlsConfig
+	}
+
+	return cfg, nil
+}
+
+// getTLSConfig returns the tls config built by the ca, cert and key files.
+func getTLSConfig(c *DBConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if c.TLSCA != "" {
+		pem, err := os.ReadFile(c.TLSCA)
+		if err != nil {
+			return nil, errors.WithMessage(err, "os.ReadFile err")
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("failed to append ca certs from: " + c.TLSCA)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if c.TLSCert != "" || c.TLSKey != "" {
+		if c.TLSCert == "" || c.TLSKey == "" {
+			return nil, errors.New("tls cert and key must be used together")
+		}
+
+		cert, err := tls.LoadX509KeyPair(c.TLSCert, c.TLSKey)
+		if err != nil {
+			return nil, errors.WithMessage(err, "tls.LoadX509KeyPair err")
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// PingDB verifies the db connection is alive.
+func PingDB(c *CmdConfig) error {
 	db, err := getDB(c)
 	if err != nil {
-		return "", err
+		return err
+	}
+
+	if err = db.Ping(); err != nil {
+		return errors.WithMessage(err, "db.Ping err")
+	}
+
+	return nil
+}
+
+// GetDatabases returns the names of databases ordered by name.
+func GetDatabases(c *CmdConfig) ([]string, error) {
+	querySQL := "SELECT SCHEMA_NAME " +
+		"FROM INFORMATION_SCHEMA.SCHEMATA " +
+		"ORDER BY SCHEMA_NAME"
+
+	return queryNames(c, querySQL)
+}
+
+// GetTables returns the names of tables in the database ordered by name.
+func GetTables(c *CmdConfig) ([]string, error) {
+	// open the db connection first to fill the database by the dsn
+	if _, err := getDB(c); err != nil {
+		return nil, err
 	}
 
-	querySQL := "SELECT TABLE_COMMENT " +
+	querySQL := "SELECT TABLE_NAME " +
 		"FROM INFORMATION_SCHEMA.TABLES " +
-		"WHERE TABLE_NAME = ?"
+		"WHERE TABLE_SCHEMA = ? " +
+		"ORDER BY TABLE_NAME"
+
+	return queryNames(c, querySQL, c.Database)
+}
 
-	rows, err := db.Query(querySQL, c.Table)
+// GetTableInfos returns the information of tables in the database ordered by name,
+// the rows are estimated by INFORMATION_SCHEMA.TABLES.
+func GetTableInfos(c *CmdConfig) ([]*TableInfo, error) {
+	db, err := getDB(c)
 	if err != nil {
-		return "", errors.WithMessage(err, "db.Query err")
+		return nil, err
+	}
+
+	querySQL := "SELECT TABLE_NAME, TABLE_TYPE, TABLE_ROWS, TABLE_COMMENT " +
+		"FROM INFORMATION_SCHEMA.TABLES " +
+		"WHERE TABLE_SCHEMA = ? " +
+		"ORDER BY TABLE_NAME"
+
+	rows, err := db.Query(querySQL, c.Database)
+	if err != nil {
+		return nil, errors.WithMessage(err, "db.Query err")
 	}
 	defer closeRows(rows)
 
-	var comment string
+	tableInfos := make([]*TableInfo, 0)
 	for rows.Next() {
-		if err = rows.Scan(&comment); err != nil {
-			return "", errors.WithMessage(err, "rows.Scan err")
+		var (
+			// TABLE_NAME, TABLE_TYPE, TABLE_COMMENT
+			tn, tt, tc string
+			// TABLE_ROWS
+			tr sql.NullInt64
+		)
+
+		if err = rows.Scan(&tn, &tt, &tr, &tc); err != nil {
+			return nil, errors.WithMessage(err, "rows.Scan err")
+		}
+		if tt == TableTypeView {
+			// the comment of view is always VIEW
+			tc = ""
 		}
+
+		tableInfos = append(tableInfos, &TableInfo{Name: tn, Type: tt, Rows: tr.Int64, Comment: tc})
 	}
 	if err = rows.Err(); err != nil {
-		return "", errors.WithMessage(err, "rows.Scan err")
+		return nil, errors.WithMessage(err, "rows.Scan err")
 	}
 
-	return comment, nil
+	return tableInfos, nil
 }
 
-// getColumnInfos returns the details of columns.
-func getColumnInfos(c *CmdConfig) ([]*ColumnInfo, error) {
-	db, err := getDB(c)
+// DescribeTable returns the schema of table, which contains the columns, indexes and foreign keys seen by grom.
+// Note that after using this function, you need to call the util.CloseDB() function to close the database.
+func DescribeTable(c *CmdConfig) (*TableSchema, error) {
+	tableSchemas, err := DescribeTables(c, []string{c.Table})
 	if err != nil {
 		return nil, err
 	}
 
-	querySQL := "SELECT COLUMN_NAME, ORDINAL_POSITION, COLUMN_DEFAULT, IS_NULLABLE, " +
-		"DATA_TYPE, CHARACTER_MAXIMUM_LENGTH, NUMERIC_PRECISION, NUMERIC_SCALE, " +
-		"COLUMN_TYPE, COLUMN_KEY, EXTRA, COLUMN_COMMENT " +
-		"FROM INFORMATION_SCHEMA.COLUMNS " +
-		"WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? " +
-		"ORDER BY ORDINAL_POSITION"
+	return tableSchemas[0], nil
+}
+
+// DescribeTables returns the schemas of tables in the order of tables, which are fetched by one catalog query
+// for each of the table comments, columns, indexes and foreign keys of all tables.
+// Note that after using this function, you need to call the util.CloseDB() function to close the database.
+func DescribeTables(c *CmdConfig, tables []string) ([]*TableSchema, error) {
+	// open the db connection first to fill the database by the dsn
+	if _, err := getDB(c); err != nil {
+		return nil, err
+	}
+
+	tableInfos, err := getTableInfos(c, tables)
+	if err != nil {
+		return nil, errors.WithMessage(err, "getTableInfos err")
+	}
+
+	var views []string
+	for _, table := range tables {
+		if ti, ok := tableInfos[table]; ok && ti.Type == TableTypeView {
+			views = append(views, table)
+		}
+	}
+	definitions, err := getViewDefinitions(c, views)
+	if err != nil {
+		return nil, errors.WithMessage(err, "getViewDefinitions err")
+	}
+
+	indexInfos, err := getIndexInfos(c, tables)
+	if err != nil {
+		return nil, errors.WithMessage(err, "getIndexInfos err")
+	}
+
+	columnInfos, err := getColumnInfos(c, tables, indexInfos)
+	if err != nil {
+		return nil, errors.WithMessage(err, "getColumnInfos err")
+	}
+
+	foreignKeyInfos, err := getForeignKeyInfos(c, tables)
+	if err != nil {
+		return nil, errors.WithMessage(err, "getForeignKeyInfos err")
+	}
+
+	checkInfos, err := getCheckInfos(c, tables)
+	if err != nil {
+		return nil, errors.WithMessage(err, "getCheckInfos err")
+	}
+
+	tableSchemas := make([]*TableSchema, 0, len(tables))
+	for _, table := range tables {
+		if len(columnInfos[table]) == 0 {
+			return nil, errors.Errorf("table %s is not found in database %s", table, c.Database)
+		}
+
+		tableIndexInfos := indexInfos[table]
+		if tableIndexInfos == nil {
+			tableIndexInfos = make([]*IndexInfo, 0)
+		}
+		ts := &TableSchema{
+			Name: table, Columns: columnInfos[table],
+			Indexes: tableIndexInfos, ForeignKeys: foreignKeyInfos[table], Checks: checkInfos[table],
+		}
+		if ti, ok := tableInfos[table]; ok {
+			ts.Comment, ts.IsView, ts.Definition = ti.Comment, ti.Type == TableTypeView, definitions[table]
+		}
+		tableSchemas = append(tableSchemas, ts)
+	}
+
+	return tableSchemas, nil
+}
+
+// queryNames returns the names queried by the sql with one string column.
+func queryNames(c *CmdConfig, querySQL string, args ...interface{}) ([]string, error) {
+	db, err := getDB(c)
+	if err != nil {
+		return nil, err
+	}
 
-	rows, err := db.Query(querySQL, c.Database, c.Table)
+	rows, err := db.Query(querySQL, args...)
 	if err != nil {
 		return nil, errors.WithMessage(err, "db.Query err")
 	}
 	defer closeRows(rows)
 
-	columnInfos := make([]*ColumnInfo, 0)
-	indexInfos, err := getIndexInfos(c)
+	names := make([]string, 0)
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return nil, errors.WithMessage(err, "rows.Scan err")
+		}
+		names = append(names, name)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.WithMessage(err, "rows.Scan err")
+	}
+
+	return names, nil
+}
+
+// queryTables queries the catalog rows of the tables by the sql, which has the placeholders of the
+// database and the tables, and the scan function is called for each row.
+func queryTables(c *CmdConfig, querySQL string, tables []string, scan func(rows *sql.Rows) error) error {
+	if len(tables) == 0 {
+		return nil
+	}
+
+	db, err := getDB(c)
 	if err != nil {
-		return nil, errors.WithMessage(err, "getIndexInfos err")
+		return err
 	}
 
+	args := make([]interface{}, 0, len(tables)+1)
+	args = append(args, c.Database)
+	for _, table := range tables {
+		args = append(args, table)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(tables)), ",")
+
+	rows, err := db.Query(fmt.Sprintf(querySQL, placeholders), args...)
+	if err != nil {
+		return errors.WithMessage(err, "db.Query err")
+	}
+	defer closeRows(rows)
+
 	for rows.Next() {
+		if err = scan(rows); err != nil {
+			return errors.WithMessage(err, "rows.Scan err")
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return errors.WithMessage(err, "rows.Scan err")
+	}
+
+	return nil
+}
+
+// getTableInfos returns the types and comments of tables keyed by the table name.
+func getTableInfos(c *CmdConfig, tables []string) (map[string]*TableInfo, error) {
+	querySQL := "SELECT TABLE_NAME, TABLE_TYPE, TABLE_COMMENT " +
+		"FROM INFORMATION_SCHEMA.TABLES " +
+		"WHERE TABLE_SCHEMA = ? AND TABLE_NAME IN (%s)"
+
+	tableInfos := make(map[string]*TableInfo, len(tables))
+	err := queryTables(c, querySQL, tables, func(rows *sql.Rows) error {
+		// TABLE_NAME, TABLE_TYPE, TABLE_COMMENT
+		var tn, tt, tc string
+		if err := rows.Scan(&tn, &tt, &tc); err != nil {
+			return err
+		}
+		if tt == TableTypeView {
+			// the comment of view is always VIEW
+			tc = ""
+		}
+		tableInfos[tn] = &TableInfo{Name: tn, Type: tt, Comment: tc}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tableInfos, nil
+}
+
+// getViewDefinitions returns the definitions of views keyed by the view name.
+func getViewDefinitions(c *CmdConfig, views []string) (map[string]string, error) {
+	querySQL := "SELECT TABLE_NAME, VIEW_DEFINITION " +
+		"FROM INFORMATION_SCHEMA.VIEWS " +
+		"WHERE TABLE_SCHEMA = ? AND TABLE_NAME IN (%s)"
+
+	definitions := make(map[string]string, len(views))
+	err := queryTables(c, querySQL, views, func(rows *sql.Rows) error {
+		// TABLE_NAME, VIEW_DEFINITION
+		var tn, vd string
+		if err := rows.Scan(&tn, &vd); err != nil {
+			return err
+		}
+		definitions[tn] = vd
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return definitions, nil
+}
+
+// getColumnInfos returns the details of columns keyed by the table name,
+// the indexes of columns are filled by the index infos. The generated columns are
+// not supported before mysql 5.7, so the query is retried without the generation
+// expression if the catalog column does not exist.
+func getColumnInfos(c *CmdConfig, tables []string, indexInfos map[string][]*IndexInfo) (map[string][]*ColumnInfo, error) {
+	columnInfos, err := queryColumnInfos(c, tables, indexInfos, "GENERATION_EXPRESSION")
+	var me *mysql.MySQLError
+	if errors.As(err, &me) && me.Number == mysqlErrUnknownColumn {
+		columnInfos, err = queryColumnInfos(c, tables, indexInfos, "NULL")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	mariaDB, err := isMariaDB(c)
+	if err != nil {
+		return nil, err
+	}
+	if mariaDB {
+		for _, cis := range columnInfos {
+			for _, ci := range cis {
+				ci.IsDefaultGenerated = ci.IsDefaultGenerated || isMariaDBExpressionDefault(ci.Default)
+			}
+		}
+	}
+
+	return columnInfos, nil
+}
+
+// isMariaDB reports whether the server is mariadb by the version.
+func isMariaDB(c *CmdConfig) (bool, error) {
+	db, err := getDB(c)
+	if err != nil {
+		return false, err
+	}
+
+	var version string
+	if err = db.QueryRow("SELECT VERSION()").Scan(&version); err != nil {
+		return false, errors.WithMessage(err, "db.QueryRow err")
+	}
+
+	return strings.Contains(strings.ToLower(version), "mariadb"), nil
+}
+
+// isMariaDBExpressionDefault reports whether the default value of mariadb is the expression,
+// mariadb quotes the string literals, so the unquoted values except NULL and numbers are expressions.
+func isMariaDBExpressionDefault(value string) bool {
+	return value != "" && !strings.EqualFold(value, "NULL") && !isNumber(value) && !strings.HasPrefix(value, "'")
+}
+
+// queryColumnInfos queries the details of columns keyed by the table name,
+// and the generation expression is selected by the expression column.
+func queryColumnInfos(c *CmdConfig, tables []string, indexInfos map[string][]*IndexInfo,
+	expressionColumn string) (map[string][]*ColumnInfo, error) {
+	querySQL := "SELECT TABLE_NAME, COLUMN_NAME, ORDINAL_POSITION, COLUMN_DEFAULT, IS_NULLABLE, " +
+		"DATA_TYPE, CHARACTER_MAXIMUM_LENGTH, NUMERIC_PRECISION, NUMERIC_SCALE, " +
+		"COLUMN_TYPE, COLUMN_KEY, EXTRA, " + expressionColumn + ", COLUMN_COMMENT " +
+		"FROM INFORMATION_SCHEMA.COLUMNS " +
+		"WHERE TABLE_SCHEMA = ? AND TABLE_NAME IN (%s) " +
+		"ORDER BY TABLE_NAME, ORDINAL_POSITION"
+
+	columnInfos := make(map[string][]*ColumnInfo, len(tables))
+	err := queryTables(c, querySQL, tables, func(rows *sql.Rows) error {
 		var (
-			// COLUMN_NAME, IS_NULLABLE, DATA_TYPE, COLUMN_TYPE, COLUMN_KEY, EXTRA, COLUMN_COMMENT
-			cn, in, dt, ct, ck, e, cc string
+			// TABLE_NAME, COLUMN_NAME, IS_NULLABLE, DATA_TYPE, COLUMN_TYPE, COLUMN_KEY, EXTRA, COLUMN_COMMENT
+			tn, cn, in, dt, ct, ck, e, cc string
 			// ORDINAL_POSITION
 			op int
-			// COLUMN_DEFAULT
-			cd sql.NullString
+			// COLUMN_DEFAULT, GENERATION_EXPRESSION
+			cd, ge sql.NullString
 			// CHARACTER_MAXIMUM_LENGTH, NUMERIC_PRECISION, NUMERIC_SCALE
 			cml, np, nc sql.NullInt64
 		)
 
-		if err = rows.Scan(&cn, &op, &cd, &in, &dt, &cml, &np, &nc, &ct, &ck, &e, &cc); err != nil {
-			return nil, errors.WithMessage(err, "rows.Scan err")
+		if err := rows.Scan(&tn, &cn, &op, &cd, &in, &dt, &cml, &np, &nc, &ct, &ck, &e, &ge, &cc); err != nil {
+			return err
 		}
 
 		ci := ColumnInfo{
@@ -130,69 +564,146 @@ func getColumnInfos(c *CmdConfig) ([]*ColumnInfo, error) {
 			IsPrimaryKey: ck == "PRI", IsAutoIncrement: strings.Contains(e, "auto_increment"),
 			IsUnsigned: strings.Contains(ct, "unsigned") && !c.DisableUnsigned, IsNullable: in == "YES",
 		}
+		ci.Generated, ci.IsDefaultGenerated, ci.IsInvisible = parseColumnExtra(e)
+		if ci.Generated != "" {
+			ci.GenerationExpression = strings.TrimSpace(ge.String)
+		}
 
-		ci.Indexes, ci.UniqueIndexes = getColumnIndexInfos(indexInfos, ci.Name)
-		columnInfos = append(columnInfos, &ci)
-	}
-	if err = rows.Err(); err != nil {
-		return nil, errors.WithMessage(err, "rows.Scan err")
+		ci.Indexes, ci.UniqueIndexes = getColumnIndexInfos(indexInfos[tn], ci.Name)
+		columnInfos[tn] = append(columnInfos[tn], &ci)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if c.EnableBeegoTag {
-		c.TableIndexes, c.TableUniques = getTableIndexes(indexInfos, c.EnableInitialism)
+	return columnInfos, nil
+}
+
+// parseColumnExtra parses the generated kind, default generated and invisible of the column extra,
+// such as VIRTUAL GENERATED, STORED GENERATED, DEFAULT_GENERATED and INVISIBLE.
+func parseColumnExtra(extra string) (generated string, isDefaultGenerated, isInvisible bool) {
+	for _, item := range strings.Fields(strings.ToUpper(extra)) {
+		switch item {
+		case GeneratedVirtual, GeneratedStored:
+			generated = item
+		case "DEFAULT_GENERATED":
+			isDefaultGenerated = true
+		case "INVISIBLE":
+			isInvisible = true
+		}
 	}
 
-	return columnInfos, nil
+	return generated, isDefaultGenerated, isInvisible
 }
 
-// getIndexInfos returns the details of indexes.
-func getIndexInfos(c *CmdConfig) ([]*IndexInfo, error) {
-	db, err := getDB(c)
+// getCheckInfos returns the check constraints keyed by the table name, the check constraints are
+// not supported before mysql 8.0.16, so nothing will be returned if the catalog table does not exist.
+func getCheckInfos(c *CmdConfig, tables []string) (map[string][]*CheckInfo, error) {
+	querySQL := "SELECT tc.TABLE_NAME, cc.CONSTRAINT_NAME, cc.CHECK_CLAUSE " +
+		"FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS tc " +
+		"JOIN INFORMATION_SCHEMA.CHECK_CONSTRAINTS cc " +
+		"ON cc.CONSTRAINT_SCHEMA = tc.CONSTRAINT_SCHEMA AND cc.CONSTRAINT_NAME = tc.CONSTRAINT_NAME " +
+		"WHERE tc.TABLE_SCHEMA = ? AND tc.TABLE_NAME IN (%s) AND tc.CONSTRAINT_TYPE = 'CHECK' " +
+		"ORDER BY tc.TABLE_NAME, cc.CONSTRAINT_NAME"
+
+	checkInfos := make(map[string][]*CheckInfo, len(tables))
+	err := queryTables(c, querySQL, tables, func(rows *sql.Rows) error {
+		// TABLE_NAME, CONSTRAINT_NAME, CHECK_CLAUSE
+		var tn, cn, cc string
+		if err := rows.Scan(&tn, &cn, &cc); err != nil {
+			return err
+		}
+		checkInfos[tn] = append(checkInfos[tn], &CheckInfo{Name: cn, Clause: cc})
+		return nil
+	})
+	var me *mysql.MySQLError
+	if errors.As(err, &me) && me.Number == mysqlErrUnknownTable {
+		return checkInfos, nil
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	querySQL := "SELECT NON_UNIQUE, INDEX_NAME, SEQ_IN_INDEX, COLUMN_NAME, INDEX_COMMENT " +
-		"FROM INFORMATION_SCHEMA.STATISTICS " +
-		"WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? " +
-		"ORDER BY INDEX_NAME, SEQ_IN_INDEX"
+	return checkInfos, nil
+}
 
-	rows, err := db.Query(querySQL, c.Database, c.Table)
-	if err != nil {
-		return nil, errors.WithMessage(err, "db.Query err")
-	}
-	defer closeRows(rows)
+// getIndexInfos returns the details of indexes keyed by the table name.
+func getIndexInfos(c *CmdConfig, tables []string) (map[string][]*IndexInfo, error) {
+	querySQL := "SELECT TABLE_NAME, NON_UNIQUE, INDEX_NAME, SEQ_IN_INDEX, COLUMN_NAME, SUB_PART, INDEX_TYPE, INDEX_COMMENT " +
+		"FROM INFORMATION_SCHEMA.STATISTICS " +
+		"WHERE TABLE_SCHEMA = ? AND TABLE_NAME IN (%s) " +
+		"ORDER BY TABLE_NAME, INDEX_NAME, SEQ_IN_INDEX"
 
-	indexInfos := make([]*IndexInfo, 0)
-	for rows.Next() {
+	indexInfos := make(map[string][]*IndexInfo, len(tables))
+	err := queryTables(c, querySQL, tables, func(rows *sql.Rows) error {
 		var (
 			// NON_UNIQUE, SEQ_IN_INDEX
 			nu, sii int
-			// INDEX_NAME, COLUMN_NAME, INDEX_COMMENT
-			in, cn, ic string
+			// TABLE_NAME, INDEX_NAME, COLUMN_NAME, INDEX_TYPE, INDEX_COMMENT
+			tn, in, cn, it, ic string
+			// SUB_PART
+			sp sql.NullInt64
 		)
 
-		if err = rows.Scan(&nu, &in, &sii, &cn, &ic); err != nil {
-			return nil, errors.WithMessage(err, "rows.Scan err")
+		if err := rows.Scan(&tn, &nu, &in, &sii, &cn, &sp, &it, &ic); err != nil {
+			return err
 		}
 
 		if in == "PRIMARY" {
-			continue
+			return nil
 		}
 
 		ii := IndexInfo{
-			Name: in, ColumnName: cn, Comment: ic, Sequence: sii, IsUnique: nu == indexUnique,
+			Name: in, ColumnName: cn, Comment: ic, Sequence: sii, Type: it, SubPart: sp.Int64, IsUnique: nu == indexUnique,
 		}
 
-		indexInfos = append(indexInfos, &ii)
+		indexInfos[tn] = append(indexInfos[tn], &ii)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	if err = rows.Err(); err != nil {
-		return nil, errors.WithMessage(err, "rows.Scan err")
+	for tn := range indexInfos {
+		indexInfos[tn] = sortIndexInfos(indexInfos[tn])
+		markCompositeIndexes(indexInfos[tn])
 	}
 
 	return indexInfos, nil
 }
 
+// getForeignKeyInfos returns the details of foreign keys keyed by the table name.
+func getForeignKeyInfos(c *CmdConfig, tables []string) (map[string][]*ForeignKeyInfo, error) {
+	querySQL := "SELECT TABLE_NAME, CONSTRAINT_NAME, COLUMN_NAME, REFERENCED_TABLE_NAME, REFERENCED_COLUMN_NAME, ORDINAL_POSITION " +
+		"FROM INFORMATION_SCHEMA.KEY_COLUMN_USAGE " +
+		"WHERE TABLE_SCHEMA = ? AND TABLE_NAME IN (%s) AND REFERENCED_TABLE_NAME IS NOT NULL " +
+		"ORDER BY TABLE_NAME, CONSTRAINT_NAME, ORDINAL_POSITION"
+
+	foreignKeyInfos := make(map[string][]*ForeignKeyInfo, len(tables))
+	err := queryTables(c, querySQL, tables, func(rows *sql.Rows) error {
+		var (
+			// TABLE_NAME, CONSTRAINT_NAME, COLUMN_NAME, REFERENCED_TABLE_NAME, REFERENCED_COLUMN_NAME
+			tn, cn, cln, rtn, rcn string
+			// ORDINAL_POSITION
+			op int
+		)
+
+		if err := rows.Scan(&tn, &cn, &cln, &rtn, &rcn, &op); err != nil {
+			return err
+		}
+
+		foreignKeyInfos[tn] = append(foreignKeyInfos[tn], &ForeignKeyInfo{
+			Name: cn, ColumnName: cln, ReferencedTable: rtn, ReferencedColumn: rcn, Sequence: op,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return foreignKeyInfos, nil
+}
+
 // getColumnIndexInfos returns the details of column indexes and column unique indexes.
 func getColumnIndexInfos(indexInfos []*IndexInfo, columnName string) (columnIndexes, columnUniques []*IndexInfo) {
 	for i := range indexInfos {
@@ -209,36 +720,84 @@ func getColumnIndexInfos(indexInfos []*IndexInfo, columnName string) (columnInde
 	return columnIndexes, columnUniques
 }
 
-// getTableIndexes returns the details of table indexes and table unique indexes.
-func getTableIndexes(indexInfos []*IndexInfo, enableInitialism ...bool) (tableIndexes, tableUniques []string) {
-	tableIndexMap, tableUniqueMap := make(map[string][]string), make(map[string][]string)
+// getTableIndexes returns the details of table indexes and table unique indexes ordered by the index name,
+// the columns of each index are in the order of SEQ_IN_INDEX, and the indexes referencing the columns
+// without field names, such as the excluded columns, are skipped.
+func getTableIndexes(indexInfos []*IndexInfo, names map[string]string) (tableIndexes, tableUniques []*TableIndex) {
+	var current *TableIndex
 
-	for i := range indexInfos {
-		indexInfo := indexInfos[i]
-		columnName := fmt.Sprintf("%q", convertName(indexInfo.ColumnName, enableInitialism...))
-		if indexInfo.IsUnique {
-			uniqueIndexes := tableUniqueMap[indexInfo.Name]
-			uniqueIndexes = append(uniqueIndexes, columnName)
-			tableUniqueMap[indexInfo.Name] = uniqueIndexes
-		} else {
-			normalIndexes := tableIndexMap[indexInfo.Name]
-			normalIndexes = append(normalIndexes, columnName)
-			tableIndexMap[indexInfo.Name] = normalIndexes
+	for _, indexInfo := range sortIndexInfos(indexInfos) {
+		if !containsIndexColumns(indexInfos, indexInfo.Name, names) {
+			continue
+		}
+		columnName := fmt.Sprintf("%q", names[indexInfo.ColumnName])
+		if current == nil || current.Name != indexInfo.Name {
+			current = &TableIndex{Name: indexInfo.Name}
+			if indexInfo.IsUnique {
+				tableUniques = append(tableUniques, current)
+			} else {
+				tableIndexes = append(tableIndexes, current)
+			}
 		}
+		current.Columns = append(current.Columns, columnName)
 	}
 
-	if len(tableUniqueMap) != 0 {
-		for indexName := range tableUniqueMap {
-			columns := tableUniqueMap[indexName]
-			tableUniques = append(tableUniques, strings.Join(columns, ","))
+	return tableIndexes, tableUniques
+}
+
+// containsIndexColumns reports whether all columns of the index have the field names.
+func containsIndexColumns(indexInfos []*IndexInfo, indexName string, names map[string]string) bool {
+	for _, indexInfo := range indexInfos {
+		if _, ok := names[indexInfo.ColumnName]; indexInfo.Name == indexName && !ok {
+			return false
 		}
 	}
-	if len(tableIndexMap) != 0 {
-		for indexName := range tableIndexMap {
-			columns := tableIndexMap[indexName]
-			tableIndexes = append(tableIndexes, strings.Join(columns, ","))
+
+	return true
+}
+
+// markCompositeIndexes marks the index infos of the indexes with multiple columns.
+func markCompositeIndexes(indexInfos []*IndexInfo) {
+	counts := make(map[string]int)
+	for _, indexInfo := range indexInfos {
+		counts[indexInfo.Name]++
+	}
+	for _, indexInfo := range indexInfos {
+		indexInfo.IsComposite = counts[indexInfo.Name] > 1
+	}
+}
+
+// getMisorderedIndexes returns the names of composite indexes whose columns in the order of SEQ_IN_INDEX
+// are not in the order of the column positions, the index infos should be sorted. The gorm v1 and xorm
+// tags have no option like the priority of gorm v2, so these indexes are created in the field order.
+func getMisorderedIndexes(ts *TableSchema) []string {
+	positions := make(map[string]int, len(ts.Columns))
+	for _, ci := range ts.Columns {
+		positions[ci.Name] = ci.Position
+	}
+
+	var names []string
+	for i := 1; i < len(ts.Indexes); i++ {
+		prev, ii := ts.Indexes[i-1], ts.Indexes[i]
+		if prev.Name == ii.Name && positions[prev.ColumnName] > positions[ii.ColumnName] &&
+			(len(names) == 0 || names[len(names)-1] != ii.Name) {
+			names = append(names, ii.Name)
 		}
 	}
 
-	return tableIndexes, tableUniques
+	return names
+}
+
+// sortIndexInfos returns the copy of index infos ordered by the index name and SEQ_IN_INDEX.
+func sortIndexInfos(indexInfos []*IndexInfo) []*IndexInfo {
+	sorted := make([]*IndexInfo, len(indexInfos))
+	copy(sorted, indexInfos)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Name != sorted[j].Name {
+			return sorted[i].Name < sorted[j].Name
+		}
+		return sorted[i].Sequence < sorted[j].Sequence
+	})
+
+	return sorted
 }
@@ -0,0 +1,204 @@
+package util
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Default value kind constants of the column.
+const (
+	defaultNone = iota
+	defaultNull
+	defaultLiteral
+	defaultCurrentTimestamp
+	defaultExpression
+)
+
+var currentTimestampRegexp = regexp.MustCompile(`(?i)^(current_timestamp|now|localtime|localtimestamp)(\(\s*([0-6]?)\s*\))?$`)
+
+// columnDefault represents the normalized default value of the column.
+type columnDefault struct {
+	kind  int
+	value string
+}
+
+// parseColumnDefault normalizes the default value of the column, which distinguishes the literals,
+// NULL, CURRENT_TIMESTAMP[(n)] and the expressions, the quoted literals of mariadb are unquoted.
+// The expressions are marked by DEFAULT_GENERATED of mysql or the unquoted form of mariadb, since
+// mysql stores the string literals without quotes, such as (none).
+func parseColumnDefault(ci *ColumnInfo) columnDefault {
+	value := strings.TrimSpace(ci.Default)
+
+	switch {
+	case value == "":
+		return columnDefault{kind: defaultNone}
+	case strings.EqualFold(value, "NULL"):
+		return columnDefault{kind: defaultNull, value: "NULL"}
+	}
+
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		return columnDefault{kind: defaultLiteral, value: strings.ReplaceAll(value[1:len(value)-1], "''", "'")}
+	}
+
+	// the string literals such as now are stored without quotes by mysql
+	if m := currentTimestampRegexp.FindStringSubmatch(value); m != nil && (isTimeColumn(ci) || ci.IsDefaultGenerated) {
+		if m[3] != "" {
+			return columnDefault{kind: defaultCurrentTimestamp, value: "CURRENT_TIMESTAMP(" + m[3] + ")"}
+		}
+		return columnDefault{kind: defaultCurrentTimestamp, value: "CURRENT_TIMESTAMP"}
+	}
+	if ci.IsDefaultGenerated || (ci.DataType == "bit" && strings.HasPrefix(strings.ToLower(value), "b'")) {
+		return columnDefault{kind: defaultExpression, value: "(" + trimCheckParens(value) + ")"}
+	}
+
+	return columnDefault{kind: defaultLiteral, value: ci.Default}
+}
+
+// isNumericColumn reports whether the column is the integer or decimal column.
+func isNumericColumn(ci *ColumnInfo) bool {
+	switch ci.DataType {
+	case "tinyint", "smallint", "mediumint", "int", "integer", "bigint",
+		"float", "double", "real", "decimal", "numeric":
+		return true
+	}
+
+	return false
+}
+
+// isNumber reports whether the value is the decimal number.
+func isNumber(value string) bool {
+	_, err := strconv.ParseFloat(value, 64)
+	return err == nil && !strings.ContainsAny(value, "xXpPeEnN_")
+}
+
+// getSQLDefault returns the default value in sql, the string literals are quoted.
+func getSQLDefault(ci *ColumnInfo, cd columnDefault) string {
+	if cd.kind == defaultLiteral && !(isNumericColumn(ci) && isNumber(cd.value)) {
+		return "'" + strings.ReplaceAll(cd.value, "'", "''") + "'"
+	}
+
+	return cd.value
+}
+
+// getGormDefault returns the default value of gorm v1 tag, which can not contain the semicolon.
+func getGormDefault(ci *ColumnInfo) string {
+	cd := parseColumnDefault(ci)
+	if cd.kind == defaultNone || cd.kind == defaultNull {
+		return ""
+	}
+
+	value := getSQLDefault(ci, cd)
+	if strings.Contains(value, ";") {
+		return ""
+	}
+
+	return value
+}
+
+// getGormV2Default returns the default value of gorm v2 tag, the semicolons are escaped by backslash.
+func getGormV2Default(ci *ColumnInfo) string {
+	cd := parseColumnDefault(ci)
+	if cd.kind == defaultNone {
+		return ""
+	}
+	if cd.kind == defaultNull {
+		return "null"
+	}
+
+	return strings.ReplaceAll(getSQLDefault(ci, cd), ";", `\;`)
+}
+
+// getXormDefault returns the default value of xorm tag, which can not contain the comma and parenthesis
+// except the expressions, since the parameters of xorm tag are split by the comma.
+func getXormDefault(ci *ColumnInfo) string {
+	cd := parseColumnDefault(ci)
+	if cd.kind == defaultNone || cd.kind == defaultNull {
+		return ""
+	}
+
+	value := getSQLDefault(ci, cd)
+	if strings.Contains(value, ",") || (cd.kind == defaultLiteral && strings.ContainsAny(value, "()")) {
+		return ""
+	}
+
+	return value
+}
+
+// getBeegoDefault returns the default value of beego orm tag, only the literals without semicolon
+// and parenthesis are supported, which are quoted by beego orm itself.
+func getBeegoDefault(ci *ColumnInfo) string {
+	cd := parseColumnDefault(ci)
+	if cd.kind != defaultLiteral || strings.ContainsAny(cd.value, ";()") {
+		return ""
+	}
+
+	return cd.value
+}
+
+// getGoDefault returns the go literal of the literal default value by the field type,
+// or empty string if the default value is not a literal or can not be converted.
+func getGoDefault(ci *ColumnInfo, fieldType string) string {
+	cd := parseColumnDefault(ci)
+	if cd.kind != defaultLiteral {
+		return ""
+	}
+	value := cd.value
+
+	switch fieldType {
+	case GoString:
+		return strconv.Quote(value)
+	case GoBytes:
+		if ci.DataType == "bit" {
+			return ""
+		}
+		return "[]byte(" + strconv.Quote(value) + ")"
+	case GoBool:
+		if b, err := strconv.ParseBool(value); err == nil {
+			return strconv.FormatBool(b)
+		}
+	case GoInt, GoInt32, GoInt64:
+		if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return value
+		}
+	case GoUint, GoUint32, GoUint64:
+		if _, err := strconv.ParseUint(value, 10, 64); err == nil {
+			return value
+		}
+	case GoFloat32, GoFloat64:
+		if isNumber(value) {
+			return value
+		}
+	case SQLNullString:
+		return "sql.NullString{String: " + strconv.Quote(value) + ", Valid: true}"
+	case SQLNullInt32, SQLNullInt64:
+		if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+			field := strings.TrimPrefix(fieldType, "sql.Null")
+			return fieldType + "{" + field + ": " + value + ", Valid: true}"
+		}
+	case SQLNullFloat64:
+		if isNumber(value) {
+			return "sql.NullFloat64{Float64: " + value + ", Valid: true}"
+		}
+	case SQLNullBool:
+		if b, err := strconv.ParseBool(value); err == nil {
+			return "sql.NullBool{Bool: " + strconv.FormatBool(b) + ", Valid: true}"
+		}
+	case GureguNullString:
+		return "null.StringFrom(" + strconv.Quote(value) + ")"
+	case GureguNullInt:
+		if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return "null.IntFrom(" + value + ")"
+		}
+	case GureguNullFloat:
+		if isNumber(value) {
+			return "null.FloatFrom(" + value + ")"
+		}
+	case GureguNullBool:
+		if b, err := strconv.ParseBool(value); err == nil {
+			return "null.BoolFrom(" + strconv.FormatBool(b) + ")"
+		}
+	}
+
+	return ""
+}
@@ -8,8 +8,11 @@ import (
 // Global variables.
 var (
 	db      *sql.DB
+	dbKey   string
 	dbMutex sync.Mutex
 
+	// commonInitialisms the default initialisms, which should not be modified,
+	// use newInitialisms to get the initialisms of each conversion.
 	commonInitialisms = map[string]struct{}{
 		"ACL":   {},
 		"API":   {},
@@ -50,11 +53,39 @@ var (
 		"XSRF":  {},
 		"XSS":   {},
 	}
+
+	// reservedFieldNames the method names of the generated model structure.
+	reservedFieldNames = []string{"TableName", "TableIndex", "TableUnique"}
+
+	// transliterations the ascii forms of latin letters with diacritics.
+	transliterations = map[rune]string{
+		'À': "A", 'Á': "A", 'Â': "A", 'Ã': "A", 'Ä': "A", 'Å': "A", 'Æ': "AE", 'Ç': "C",
+		'È': "E", 'É': "E", 'Ê': "E", 'Ë': "E", 'Ì': "I", 'Í': "I", 'Î': "I", 'Ï': "I",
+		'Ð': "D", 'Ñ': "N", 'Ò': "O", 'Ó': "O", 'Ô': "O", 'Õ': "O", 'Ö': "O", 'Ø': "O",
+		'Ù': "U", 'Ú': "U", 'Û': "U", 'Ü': "U", 'Ý': "Y", 'Þ': "TH", 'ß': "ss",
+		'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'æ': "ae", 'ç': "c",
+		'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ì': "i", 'í': "i", 'î': "i", 'ï': "i",
+		'ð': "d", 'ñ': "n", 'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o",
+		'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ý': "y", 'þ': "th", 'ÿ': "y",
+		'Œ': "OE", 'œ': "oe", 'Š': "S", 'š': "s", 'Ž': "Z", 'ž': "z", 'Ł': "L", 'ł': "l",
+	}
 )
 
 const (
 	// MySQLDriverName represents the mysql driver name.
 	MySQLDriverName = "mysql"
+	// defaultMySQLPort the default port of mysql.
+	defaultMySQLPort = 3306
+	// defaultMySQLCharset the default charset of the mysql connection.
+	defaultMySQLCharset = "utf8"
+	// mysqlErrUnknownTable the mysql error number of the unknown table.
+	mysqlErrUnknownTable = 1109
+	// mysqlErrUnknownColumn the mysql error number of the unknown column.
+	mysqlErrUnknownColumn = 1054
+	// SchemaVersion the version of the machine-readable schema format.
+	SchemaVersion = 1
+	// exportedPrefix the prefix of the converted name that does not start with upper case letter.
+	exportedPrefix = "X"
 )
 
 const (
@@ -62,6 +93,53 @@ const (
 	indexNormal = 1
 )
 
+// Table type constants of INFORMATION_SCHEMA.TABLES.
+const (
+	TableTypeBase = "BASE TABLE"
+	TableTypeView = "VIEW"
+)
+
+// Generated column constants of the EXTRA of INFORMATION_SCHEMA.COLUMNS.
+const (
+	GeneratedVirtual = "VIRTUAL"
+	GeneratedStored  = "STORED"
+)
+
+// View mode constants, which decide whether the views are matched by the table patterns.
+const (
+	ViewsInclude = "include"
+	ViewsExclude = "exclude"
+	ViewsOnly    = "only"
+)
+
+// Naming strategy constants of the serialization tags.
+const (
+	NamingRaw    = "raw"
+	NamingSnake  = "snake"
+	NamingCamel  = "camel"
+	NamingPascal = "pascal"
+	NamingKebab  = "kebab"
+)
+
+// Validate tag rule constants, which can be disabled by ValidateTagConfig.DisabledRules.
+const (
+	ValidateRuleRequired = "required"
+	ValidateRuleLength   = "length"
+	ValidateRuleUnsigned = "unsigned"
+	ValidateRuleEnum     = "enum"
+	ValidateRuleRange    = "range"
+	ValidateRuleCheck    = "check"
+)
+
+// Convention rule constants, which can be disabled by ConventionConfig.DisabledRules.
+const (
+	ConventionCreatedAt = "created_at"
+	ConventionUpdatedAt = "updated_at"
+	ConventionDeletedAt = "deleted_at"
+	ConventionVersion   = "version"
+	ConventionIsDeleted = "is_deleted"
+)
+
 // Global data type constants.
 const (
 	GureguNullString = "null.String"
@@ -90,39 +168,149 @@ const (
 	GoBool        = "bool"
 	GoTime        = "time.Time"
 	GoPointerTime = "*time.Time"
+
+	GormDeletedAt       = "gorm.DeletedAt"
+	SoftDeleteDeletedAt = "soft_delete.DeletedAt"
 )
 
 // CmdConfig represents the config of the running grom command line.
 type CmdConfig struct {
 	DBConfig
-	PackageName        string   `json:"package_name"`
-	StructName         string   `json:"struct_name"`
-	EnableInitialism   bool     `json:"enable_initialism"`
-	EnableFieldComment bool     `json:"enable_field_comment"`
-	EnableSQLNull      bool     `json:"enable_sql_null"`
-	EnableGureguNull   bool     `json:"enable_guregu_null"`
-	EnableJSONTag      bool     `json:"enable_json_tag"`
-	EnableXMLTag       bool     `json:"enable_xml_tag"`
-	EnableGormTag      bool     `json:"enable_gorm_tag"`
-	EnableXormTag      bool     `json:"enable_xorm_tag"`
-	EnableBeegoTag     bool     `json:"enable_beego_tag"`
-	EnableGoroseTag    bool     `json:"enable_gorose_tag"`
-	EnableGormV2Tag    bool     `json:"enable_gorm_v2_tag"`
-	DisableUnsigned    bool     `json:"disable_unsigned"`
-	EnableGoTime       bool     `json:"-"`
-	TableComment       string   `json:"-"`
-	TableIndexes       []string `json:"-"`
-	TableUniques       []string `json:"-"`
+	PackageName           string            `json:"package_name"`
+	StructName            string            `json:"struct_name"`
+	EnableInitialism      bool              `json:"enable_initialism"`
+	EnableFieldComment    bool              `json:"enable_field_comment"`
+	EnableSQLNull         bool              `json:"enable_sql_null"`
+	EnableGureguNull      bool              `json:"enable_guregu_null"`
+	EnableJSONTag         bool              `json:"enable_json_tag"`
+	EnableXMLTag          bool              `json:"enable_xml_tag"`
+	EnableGormTag         bool              `json:"enable_gorm_tag"`
+	EnableXormTag         bool              `json:"enable_xorm_tag"`
+	EnableBeegoTag        bool              `json:"enable_beego_tag"`
+	EnableGoroseTag       bool              `json:"enable_gorose_tag"`
+	EnableGormV2Tag       bool              `json:"enable_gorm_v2_tag"`
+	EnableValidateTag     bool              `json:"enable_validate_tag"`
+	EnableYAMLTag         bool              `json:"enable_yaml_tag"`
+	EnableTOMLTag         bool              `json:"enable_toml_tag"`
+	EnableBSONTag         bool              `json:"enable_bson_tag"`
+	EnableMsgpackTag      bool              `json:"enable_msgpack_tag"`
+	EnableMapstructureTag bool              `json:"enable_mapstructure_tag"`
+	EnableFormTag         bool              `json:"enable_form_tag"`
+	EnableSingularTable   bool              `json:"enable_singular_table"`
+	EnableViewDefinition  bool              `json:"enable_view_definition"`
+	EnableConstructor     bool              `json:"enable_constructor"`
+	DisableUnsigned       bool              `json:"disable_unsigned"`
+	TablePrefixes         []string          `json:"table_prefixes,omitempty"`
+	TableSuffixes         []string          `json:"table_suffixes,omitempty"`
+	SingularExceptions    map[string]string `json:"singular_exceptions,omitempty"`
+	Initialisms           []string          `json:"initialisms,omitempty"`
+	JSONTag               TagConfig         `json:"json_tag"`
+	XMLTag                TagConfig         `json:"xml_tag"`
+	YAMLTag               TagConfig         `json:"yaml_tag"`
+	TOMLTag               TagConfig         `json:"toml_tag"`
+	BSONTag               TagConfig         `json:"bson_tag"`
+	MsgpackTag            TagConfig         `json:"msgpack_tag"`
+	MapstructureTag       TagConfig         `json:"mapstructure_tag"`
+	FormTag               TagConfig         `json:"form_tag"`
+	CustomTags            []CustomTagConfig `json:"custom_tags,omitempty"`
+	ValidateTag           ValidateTagConfig `json:"validate_tag"`
+	Conventions           ConventionConfig  `json:"conventions"`
+	BaseModel             BaseModelConfig   `json:"base_model"`
+	IncludeColumns        []string          `json:"include_columns,omitempty"`
+	ExcludeColumns        []string          `json:"exclude_columns,omitempty"`
+	RenameColumns         map[string]string `json:"rename_columns,omitempty"`
+	SchemaFile            string            `json:"schema_file,omitempty"`
+	SnapshotFile          string            `json:"snapshot_file,omitempty"`
+	Views                 string            `json:"views,omitempty"`
+	TableComment          string            `json:"-"`
+	TableChecks           []*CheckInfo      `json:"-"`
+	IsView                bool              `json:"-"`
+	ViewDefinition        string            `json:"-"`
+	TableIndexes          []*TableIndex     `json:"-"`
+	TableUniques          []*TableIndex     `json:"-"`
+	Verbose               bool              `json:"-"`
+	initialisms           map[string]string
+	unknownKeys           []string
+
+	// Deprecated: the imports are derived from the field types,
+	// EnableGoTime only reports whether the converted fields contain time.Time.
+	EnableGoTime bool `json:"-"`
+}
+
+// Manifest represents the project manifest, which lists the targets inheriting the shared defaults.
+type Manifest struct {
+	Targets []*ManifestTarget
+}
+
+// ManifestTarget represents the target of the project manifest, the table of config is written into
+// the output file, or the tables matched by the patterns are written into the output directory.
+type ManifestTarget struct {
+	Config CmdConfig
+	Output string
+	Tables []string
+}
+
+// ConfigError represents all the problems found by validating the command config.
+type ConfigError struct {
+	Problems []string
+}
+
+// TagConfig represents the config of the generated serialization tag, such as json and xml.
+type TagConfig struct {
+	Naming        string   `json:"naming,omitempty"`
+	OmitEmpty     bool     `json:"omit_empty,omitempty"`
+	StringBigint  bool     `json:"string_bigint,omitempty"`
+	HiddenColumns []string `json:"hidden_columns,omitempty"`
+}
+
+// CustomTagConfig represents the config of the custom tag, the value is a text/template
+// executed with the ColumnInfo, such as {{ .Name | camel }}.
+type CustomTagConfig struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// ValidateTagConfig represents the config of the generated validate tag.
+type ValidateTagConfig struct {
+	DisabledRules []string          `json:"disabled_rules,omitempty"`
+	SkipColumns   []string          `json:"skip_columns,omitempty"`
+	ColumnRules   map[string]string `json:"column_rules,omitempty"`
+}
+
+// ConventionConfig represents the config of the convention rules, which map the soft delete, timestamp
+// and version columns to the orm features, the columns of each rule are the rule name by default.
+type ConventionConfig struct {
+	DisabledRules []string            `json:"disabled_rules,omitempty"`
+	Columns       map[string][]string `json:"columns,omitempty"`
+}
+
+// BaseModelConfig represents the config of the embedded base model, such as gorm.Model, the columns
+// map the column names to the field types, which are the builtin ones of gorm.Model if not configured.
+type BaseModelConfig struct {
+	Name    string            `json:"name,omitempty"`
+	Import  string            `json:"import,omitempty"`
+	Columns map[string]string `json:"columns,omitempty"`
 }
 
 // DBConfig represents the config of the connected database.
 type DBConfig struct {
-	Host     string `json:"host"`
-	Port     int    `json:"port"`
-	User     string `json:"user"`
-	Password string `json:"password"`
-	Database string `json:"database"`
-	Table    string `json:"table"`
+	DSN          string            `json:"dsn,omitempty"`
+	Host         string            `json:"host"`
+	Port         int               `json:"port"`
+	Socket       string            `json:"socket,omitempty"`
+	User         string            `json:"user"`
+	Password     string            `json:"password,omitempty"`
+	PasswordFile string            `json:"password_file,omitempty"`
+	DefaultsFile string            `json:"defaults_file,omitempty"`
+	LoginPath    string            `json:"login_path,omitempty"`
+	Database     string            `json:"database"`
+	Table        string            `json:"table"`
+	Charset      string            `json:"charset,omitempty"`
+	TLS          string            `json:"tls,omitempty"`
+	TLSCA        string            `json:"tls_ca,omitempty"`
+	TLSCert      string            `json:"tls_cert,omitempty"`
+	TLSKey       string            `json:"tls_key,omitempty"`
+	Params       map[string]string `json:"params,omitempty"`
 }
 
 // StructField represents the field of the generated model structure.
@@ -133,34 +321,126 @@ type StructField struct {
 	Comment      string
 	RawName      string
 	Default      string
+	DefaultValue string
 	IsPrimaryKey bool
 	IsNullable   bool
+	IsEmbedded   bool
+}
+
+// TableIndex represents the named index of the generated TableIndex or TableUnique method,
+// the columns are the quoted field names in the order of SEQ_IN_INDEX.
+type TableIndex struct {
+	Name    string
+	Columns []string
+}
+
+// TableOutput represents the generated model structure of the table.
+type TableOutput struct {
+	Table  string
+	Output string
+}
+
+// TableInfo represents the information of the table.
+type TableInfo struct {
+	Name    string `json:"name" mysql:"TABLE_NAME"`
+	Type    string `json:"type" mysql:"TABLE_TYPE"`
+	Rows    int64  `json:"rows" mysql:"TABLE_ROWS"`
+	Comment string `json:"comment" mysql:"TABLE_COMMENT"`
+}
+
+// TableSchema represents the schema of the table, including the columns, indexes and foreign keys.
+type TableSchema struct {
+	Name        string            `json:"name"`
+	Comment     string            `json:"comment"`
+	Columns     []*ColumnInfo     `json:"columns"`
+	Indexes     []*IndexInfo      `json:"indexes"`
+	ForeignKeys []*ForeignKeyInfo `json:"foreign_keys,omitempty"`
+	Checks      []*CheckInfo      `json:"checks,omitempty"`
+	IsView      bool              `json:"is_view,omitempty"`
+	Definition  string            `json:"definition,omitempty"`
+}
+
+// Snapshot represents the schema snapshot of the tables in the database,
+// which can be used to generate the model structures offline.
+type Snapshot struct {
+	Version  int            `json:"version"`
+	Database string         `json:"database"`
+	Tables   []*TableSchema `json:"tables"`
+}
+
+// Schema represents the machine-readable schema of the converted table in the versioned format,
+// which can be used as the schema source of the conversion instead of connecting to mysql.
+type Schema struct {
+	Version    int            `json:"version"`
+	Table      string         `json:"table"`
+	Comment    string         `json:"comment"`
+	StructName string         `json:"struct_name,omitempty"`
+	IsView     bool           `json:"is_view,omitempty"`
+	Definition string         `json:"definition,omitempty"`
+	Columns    []*ColumnInfo  `json:"columns"`
+	Indexes    []*IndexInfo   `json:"indexes"`
+	Checks     []*CheckInfo   `json:"checks,omitempty"`
+	Fields     []*SchemaField `json:"fields,omitempty"`
+}
+
+// SchemaField represents the derived field of the generated model structure in the schema.
+type SchemaField struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Tag    string `json:"tag"`
+	Column string `json:"column"`
 }
 
 // ColumnInfo represents the information of the column.
 type ColumnInfo struct {
-	Name            string       `mysql:"COLUMN_NAME"`
-	DataType        string       `mysql:"DATA_TYPE"`
-	Type            string       `mysql:"COLUMN_TYPE"`
-	Default         string       `mysql:"COLUMN_DEFAULT"`
-	Comment         string       `mysql:"COLUMN_COMMENT"`
-	Length          int64        `mysql:"CHARACTER_MAXIMUM_LENGTH"`
-	Precision       int64        `mysql:"NUMERIC_PRECISION"`
-	Scale           int64        `mysql:"NUMERIC_SCALE"`
-	Position        int          `mysql:"ORDINAL_POSITION"`
-	IsPrimaryKey    bool         `mysql:"COLUMN_KEY"`
-	IsAutoIncrement bool         `mysql:"EXTRA"`
-	IsUnsigned      bool         `mysql:"COLUMN_TYPE"`
-	IsNullable      bool         `mysql:"IS_NULLABLE"`
-	Indexes         []*IndexInfo `mysql:"-"`
-	UniqueIndexes   []*IndexInfo `mysql:"-"`
+	Name                 string       `json:"name" mysql:"COLUMN_NAME"`
+	DataType             string       `json:"data_type" mysql:"DATA_TYPE"`
+	Type                 string       `json:"type" mysql:"COLUMN_TYPE"`
+	Default              string       `json:"default" mysql:"COLUMN_DEFAULT"`
+	Comment              string       `json:"comment" mysql:"COLUMN_COMMENT"`
+	Length               int64        `json:"length" mysql:"CHARACTER_MAXIMUM_LENGTH"`
+	Precision            int64        `json:"precision" mysql:"NUMERIC_PRECISION"`
+	Scale                int64        `json:"scale" mysql:"NUMERIC_SCALE"`
+	Position             int          `json:"position" mysql:"ORDINAL_POSITION"`
+	IsPrimaryKey         bool         `json:"is_primary_key" mysql:"COLUMN_KEY"`
+	IsAutoIncrement      bool         `json:"is_auto_increment" mysql:"EXTRA"`
+	IsUnsigned           bool         `json:"is_unsigned" mysql:"COLUMN_TYPE"`
+	IsNullable           bool         `json:"is_nullable" mysql:"IS_NULLABLE"`
+	Generated            string       `json:"generated,omitempty" mysql:"EXTRA"`
+	GenerationExpression string       `json:"generation_expression,omitempty" mysql:"GENERATION_EXPRESSION"`
+	IsDefaultGenerated   bool         `json:"is_default_generated,omitempty" mysql:"EXTRA"`
+	IsInvisible          bool         `json:"is_invisible,omitempty" mysql:"EXTRA"`
+	IsReadOnly           bool         `json:"-" mysql:"-"`
+	Convention           string       `json:"-" mysql:"-"`
+	DeletedAtField       string       `json:"-" mysql:"-"`
+	Indexes              []*IndexInfo `json:"-" mysql:"-"`
+	UniqueIndexes        []*IndexInfo `json:"-" mysql:"-"`
+	Checks               []*CheckInfo `json:"-" mysql:"-"`
 }
 
 // IndexInfo represents the information of the index.
 type IndexInfo struct {
-	Name       string `mysql:"INDEX_NAME"`
-	ColumnName string `mysql:"COLUMN_NAME"`
-	Comment    string `mysql:"INDEX_COMMENT"`
-	Sequence   int    `mysql:"SEQ_IN_INDEX"`
-	IsUnique   bool   `mysql:"NON_UNIQUE"`
+	Name        string `json:"name" mysql:"INDEX_NAME"`
+	ColumnName  string `json:"column_name" mysql:"COLUMN_NAME"`
+	Comment     string `json:"comment" mysql:"INDEX_COMMENT"`
+	Sequence    int    `json:"sequence" mysql:"SEQ_IN_INDEX"`
+	Type        string `json:"type,omitempty" mysql:"INDEX_TYPE"`
+	SubPart     int64  `json:"sub_part,omitempty" mysql:"SUB_PART"`
+	IsUnique    bool   `json:"is_unique" mysql:"NON_UNIQUE"`
+	IsComposite bool   `json:"-" mysql:"-"`
+}
+
+// ForeignKeyInfo represents the information of the foreign key.
+type ForeignKeyInfo struct {
+	Name             string `json:"name" mysql:"CONSTRAINT_NAME"`
+	ColumnName       string `json:"column_name" mysql:"COLUMN_NAME"`
+	ReferencedTable  string `json:"referenced_table" mysql:"REFERENCED_TABLE_NAME"`
+	ReferencedColumn string `json:"referenced_column" mysql:"REFERENCED_COLUMN_NAME"`
+	Sequence         int    `json:"sequence" mysql:"ORDINAL_POSITION"`
+}
+
+// CheckInfo represents the information of the check constraint.
+type CheckInfo struct {
+	Name   string `json:"name" mysql:"CONSTRAINT_NAME"`
+	Clause string `json:"clause" mysql:"CHECK_CLAUSE"`
 }
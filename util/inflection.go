@@ -0,0 +1,107 @@
+package util
+
+import (
+	"regexp"
+	"strings"
+)
+
+// singularRule represents the rule of converting the plural word to singular word.
+type singularRule struct {
+	regexp      *regexp.Regexp
+	replacement string
+}
+
+var (
+	// uncountableWords the words that have no singular form.
+	uncountableWords = map[string]struct{}{
+		"data":        {},
+		"equipment":   {},
+		"fish":        {},
+		"information": {},
+		"jeans":       {},
+		"metadata":    {},
+		"money":       {},
+		"news":        {},
+		"police":      {},
+		"rice":        {},
+		"series":      {},
+		"sheep":       {},
+		"species":     {},
+		"status":      {},
+	}
+
+	// irregularWords the plural words with irregular singular form.
+	irregularWords = map[string]string{
+		"children": "child",
+		"feet":     "foot",
+		"geese":    "goose",
+		"men":      "man",
+		"mice":     "mouse",
+		"oxen":     "ox",
+		"people":   "person",
+		"teeth":    "tooth",
+		"women":    "woman",
+	}
+
+	// singularRules the rules of converting the plural word to singular word, the first matched rule will be used,
+	// the rules of short stems are anchored to the whole word, such as taxes and databases are not taxis and databasis.
+	singularRules = newSingularRules([][2]string{
+		{`(quiz)zes$`, "${1}"},
+		{`(matr)ices$`, "${1}ix"},
+		{`(vert|ind)ices$`, "${1}ex"},
+		{`(alias|status)es$`, "${1}"},
+		{`(octop|vir)i$`, "${1}us"},
+		{`^(cris|ax|test)es$`, "${1}is"},
+		{`(analy|diagno|parenthe|progno|synop|the)ses$`, "${1}sis"},
+		{`^(ba)ses$`, "${1}sis"},
+		{`(shoe)s$`, "${1}"},
+		{`(o)es$`, "${1}"},
+		{`(bus)es$`, "${1}"},
+		{`(x|ch|ss|sh|zz)es$`, "${1}"},
+		{`(m)ovies$`, "${1}ovie"},
+		{`([^aeiouy]|qu)ies$`, "${1}y"},
+		{`(hive|tive)s$`, "${1}"},
+		{`([lr])ves$`, "${1}f"},
+		{`([^f])ves$`, "${1}fe"},
+		{`(ss|us|is)$`, "${1}"},
+		{`s$`, ""},
+	})
+)
+
+// newSingularRules returns the case-insensitive singular rules.
+func newSingularRules(rules [][2]string) []*singularRule {
+	result := make([]*singularRule, 0, len(rules))
+	for _, r := range rules {
+		result = append(result, &singularRule{
+			regexp:      regexp.MustCompile("(?i)" + r[0]),
+			replacement: r[1],
+		})
+	}
+
+	return result
+}
+
+// singularize converts the plural word to singular word by english inflection rules,
+// the exceptions map will be checked first.
+func singularize(word string, exceptions map[string]string) string {
+	lowerWord := strings.ToLower(word)
+	for plural, singular := range exceptions {
+		if strings.ToLower(plural) == lowerWord {
+			return singular
+		}
+	}
+	if _, ok := uncountableWords[lowerWord]; ok {
+		return word
+	}
+	if singular, ok := irregularWords[lowerWord]; ok {
+		return singular
+	}
+
+	for _, rule := range singularRules {
+		if rule.regexp.MatchString(word) {
+			return rule.regexp.ReplaceAllString(word, rule.replacement)
+		}
+	}
+
+	return word
+}
@@ -0,0 +1,156 @@
+package util
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// manifestDefaultsKey the key of the shared defaults in the manifest.
+	manifestDefaultsKey = "defaults"
+	// manifestTargetsKey the key of the targets in the manifest.
+	manifestTargetsKey = "targets"
+	// manifestOutputKey the key of the output path of the target.
+	manifestOutputKey = "output"
+	// manifestTablesKey the key of the table patterns of the target.
+	manifestTablesKey = "tables"
+)
+
+// LoadManifest loads the project manifest from the json, yaml or toml file, each target inherits the
+// shared defaults recursively, and the relative paths are resolved by the directory of the manifest.
+func LoadManifest(filePath string) (*Manifest, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, errors.WithMessage(err, "os.ReadFile err")
+	}
+
+	values, err := unmarshalConfigValues(content, GetConfigFormat(filePath))
+	if err != nil {
+		return nil, err
+	}
+
+	defaults, ok := values[manifestDefaultsKey].(map[string]interface{})
+	if !ok && values[manifestDefaultsKey] != nil {
+		return nil, errors.New("defaults of manifest must be a map")
+	}
+	targets, ok := values[manifestTargetsKey].([]interface{})
+	if !ok || len(targets) == 0 {
+		return nil, errors.New("targets of manifest are required")
+	}
+
+	var unknownKeys []string
+	for key := range values {
+		if key != manifestDefaultsKey && key != manifestTargetsKey {
+			unknownKeys = append(unknownKeys, key)
+		}
+	}
+
+	dir := filepath.Dir(filePath)
+	manifest := &Manifest{Targets: make([]*ManifestTarget, 0, len(targets))}
+	for i, target := range targets {
+		targetValues, ok := target.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("target of manifest must be a map, target: " + strconv.Itoa(i))
+		}
+
+		mt, err := newManifestTarget(copyConfigValues(defaults), copyConfigValues(targetValues), dir)
+		if err != nil {
+			return nil, errors.WithMessage(err, "invalid target "+strconv.Itoa(i))
+		}
+		mt.Config.unknownKeys = append(mt.Config.unknownKeys, unknownKeys...)
+		manifest.Targets = append(manifest.Targets, mt)
+	}
+
+	return manifest, nil
+}
+
+// newManifestTarget returns the manifest target by merging the target values into the defaults.
+func newManifestTarget(defaults, values map[string]interface{}, dir string) (*ManifestTarget, error) {
+	mt := &ManifestTarget{}
+
+	if output, ok := values[manifestOutputKey]; ok {
+		mt.Output, _ = output.(string)
+		delete(values, manifestOutputKey)
+	}
+	if tables, ok := values[manifestTablesKey]; ok {
+		items, _ := tables.([]interface{})
+		for _, item := range items {
+			if table, ok := item.(string); ok {
+				mt.Tables = append(mt.Tables, table)
+			}
+		}
+		delete(values, manifestTablesKey)
+	}
+	if mt.Output == "" {
+		return nil, errors.New("output is required")
+	}
+	if len(mt.Tables) != 0 {
+		if _, ok := values["table"]; ok {
+			return nil, errors.New("table and tables are mutually exclusive")
+		}
+		delete(defaults, "table")
+	}
+
+	values = mergeConfigValues(defaults, values)
+	unknownKeys := collectUnknownKeys(values, reflect.TypeOf(CmdConfig{}), "")
+	values = expandConfigValues(values, getConfigFields()).(map[string]interface{})
+
+	b, err := json.Marshal(values)
+	if err != nil {
+		return nil, errors.WithMessage(err, "json.Marshal err")
+	}
+	if err = json.Unmarshal(b, &mt.Config); err != nil {
+		return nil, errors.WithMessage(err, "json.Unmarshal err")
+	}
+	mt.Config.unknownKeys = unknownKeys
+
+	mt.Output = resolvePath(dir, mt.Output)
+	for _, p := range []*string{
+		&mt.Config.SchemaFile, &mt.Config.SnapshotFile, &mt.Config.PasswordFile,
+		&mt.Config.DefaultsFile, &mt.Config.TLSCA, &mt.Config.TLSCert, &mt.Config.TLSKey,
+	} {
+		*p = resolvePath(dir, *p)
+	}
+
+	return mt, nil
+}
+
+// copyConfigValues returns the deep copy of config values.
+func copyConfigValues(values map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		result[key] = copyConfigValue(value)
+	}
+
+	return result
+}
+
+// copyConfigValue returns the deep copy of config value.
+func copyConfigValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return copyConfigValues(v)
+	case []interface{}:
+		items := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			items = append(items, copyConfigValue(item))
+		}
+		return items
+	default:
+		return v
+	}
+}
+
+// resolvePath resolves the relative path by the directory, "-" and empty path are kept.
+func resolvePath(dir, path string) string {
+	if path == "" || path == stdinFileName || filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(dir, path)
+}
@@ -0,0 +1,119 @@
+package util
+
+import (
+	"strings"
+	"unicode"
+)
+
+// getStructTableName returns the table name used to generate the struct name,
+// the table prefixes and suffixes will be stripped and the last word of it will be singularized.
+func getStructTableName(cc *CmdConfig) string {
+	name := cc.Table
+
+	for _, prefix := range cc.TablePrefixes {
+		if prefix != "" && strings.HasPrefix(name, prefix) && len(name) > len(prefix) {
+			name = strings.TrimPrefix(name, prefix)
+			break
+		}
+	}
+	for _, suffix := range cc.TableSuffixes {
+		if suffix != "" && strings.HasSuffix(name, suffix) && len(name) > len(suffix) {
+			name = strings.TrimSuffix(name, suffix)
+			break
+		}
+	}
+
+	if cc.EnableSingularTable {
+		if singular, ok := cc.SingularExceptions[name]; ok {
+			return singular
+		}
+
+		i := strings.LastIndex(name, "_") + 1
+		name = name[:i] + singularize(name[i:], cc.SingularExceptions)
+	}
+
+	return name
+}
+
+// convertCase converts the name to the naming strategy case name.
+func convertCase(name, naming string) string {
+	switch strings.ToLower(naming) {
+	case NamingSnake:
+		return strings.Join(lowerWords(splitWords(name)), "_")
+	case NamingKebab:
+		return strings.Join(lowerWords(splitWords(name)), "-")
+	case NamingCamel:
+		words := splitWords(name)
+		for i := range words {
+			if i == 0 {
+				words[i] = strings.ToLower(words[i])
+			} else {
+				words[i] = titleWord(words[i])
+			}
+		}
+		return strings.Join(words, "")
+	case NamingPascal:
+		words := splitWords(name)
+		for i := range words {
+			words[i] = titleWord(words[i])
+		}
+		return strings.Join(words, "")
+	default:
+		return name
+	}
+}
+
+// splitWords splits the name into words by separators and case changes,
+// such as user_name, user-name, userName, UserName and USER_NAME.
+func splitWords(name string) []string {
+	var (
+		words []string
+		word  []rune
+	)
+
+	runes := []rune(name)
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = word[:0]
+		}
+	}
+
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(r) && len(word) > 0 {
+			prev := word[len(word)-1]
+			// split userName into user and Name, HTTPServer into HTTP and Server
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+				(unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				flush()
+			}
+		}
+		word = append(word, r)
+	}
+	flush()
+
+	return words
+}
+
+// lowerWords converts the words to lower case.
+func lowerWords(words []string) []string {
+	for i := range words {
+		words[i] = strings.ToLower(words[i])
+	}
+
+	return words
+}
+
+// titleWord converts the first letter of word to upper case and the rest to lower case.
+func titleWord(word string) string {
+	runes := []rune(strings.ToLower(word))
+	if len(runes) > 0 {
+		runes[0] = unicode.ToUpper(runes[0])
+	}
+
+	return string(runes)
+}
@@ -0,0 +1,35 @@
+//go:build go1.18
+// +build go1.18
+
+package util
+
+import (
+	"go/token"
+	"testing"
+)
+
+func FuzzConvertName(f *testing.F) {
+	for _, seed := range []string{
+		"user_name", "2fa_enabled", "type", "order-id", "user.name", "first name",
+		"测试", "用户_名称", "café", "price_€", "___", "\xff", "á",
+	} {
+		f.Add(seed, true)
+	}
+
+	f.Fuzz(func(t *testing.T, name string, enable bool) {
+		initialisms := newInitialisms(enable, []string{"OAuth", "iOS"})
+		output := convertName(name, initialisms)
+		if name == "" {
+			if output != "" {
+				t.Errorf("convertName failed, input:%q, expectation empty, output:%q", name, output)
+			}
+			return
+		}
+		if !token.IsIdentifier(output) || !token.IsExported(output) {
+			t.Errorf("convertName failed, input:%q, output:%q is not an exported identifier", name, output)
+		}
+		if again := convertName(name, initialisms); again != output {
+			t.Errorf("convertName is not deterministic, input:%q, outputs:%q and %q", name, output, again)
+		}
+	})
+}
@@ -0,0 +1,305 @@
+package util
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// GetSchema gets the machine-readable schema of the table, which contains the table comment,
+// columns, indexes and the derived structure fields.
+// Note that after using this function, you need to call the util.CloseDB() function to close the database.
+func GetSchema(cc *CmdConfig) (*Schema, error) {
+	ts, err := getTableSchema(cc)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := getFields(cc, ts)
+	if err != nil {
+		return nil, err
+	}
+
+	schemaFields := make([]*SchemaField, 0, len(fields))
+	for _, field := range fields {
+		schemaFields = append(schemaFields, &SchemaField{
+			Name: field.Name, Type: field.Type, Tag: strings.Trim(field.Tag, "`"), Column: field.RawName,
+		})
+	}
+
+	return &Schema{
+		Version:    SchemaVersion,
+		Table:      ts.Name,
+		Comment:    ts.Comment,
+		StructName: cc.StructName,
+		IsView:     ts.IsView,
+		Definition: ts.Definition,
+		Columns:    ts.Columns,
+		Indexes:    ts.Indexes,
+		Checks:     ts.Checks,
+		Fields:     schemaFields,
+	}, nil
+}
+
+// LoadSchema loads the machine-readable schema from the json file.
+func LoadSchema(filePath string) (*Schema, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, errors.WithMessage(err, "os.ReadFile err")
+	}
+
+	schema := &Schema{}
+	if err = json.Unmarshal(content, schema); err != nil {
+		return nil, errors.WithMessage(err, "json.Unmarshal err")
+	}
+	if schema.Version < 1 || schema.Version > SchemaVersion {
+		return nil, errors.New("unsupported schema version: " + strconv.Itoa(schema.Version))
+	}
+	if schema.Table == "" || len(schema.Columns) == 0 {
+		return nil, errors.New("table and columns of schema are required")
+	}
+
+	return schema, nil
+}
+
+// GetSnapshot gets the schema snapshot of the tables in the database, the tables are matched by the
+// include patterns and not matched by the exclude patterns, such as user_* and *_bak,
+// all tables are included if no include pattern is given.
+// Note that after using this function, you need to call the util.CloseDB() function to close the database.
+func GetSnapshot(c *CmdConfig, includes, excludes []string) (*Snapshot, error) {
+	tables, err := MatchTables(c, includes, excludes)
+	if err != nil {
+		return nil, err
+	}
+
+	tableSchemas, err := DescribeTables(c, tables)
+	if err != nil {
+		return nil, errors.WithMessage(err, "DescribeTables err")
+	}
+
+	return &Snapshot{Version: SchemaVersion, Database: c.Database, Tables: tableSchemas}, nil
+}
+
+// MatchTables returns the tables in the database or the snapshot file, which are matched by the include
+// patterns and not matched by the exclude patterns, all tables are included if no include pattern is given.
+// The views are included, excluded or only matched by the views mode of config.
+func MatchTables(c *CmdConfig, includes, excludes []string) ([]string, error) {
+	var tables []string
+	views := strings.ToLower(c.Views)
+
+	if c.SnapshotFile != "" {
+		snapshot, err := LoadSnapshot(c.SnapshotFile)
+		if err != nil {
+			return nil, errors.WithMessage(err, "LoadSnapshot err")
+		}
+		for _, ts := range snapshot.Tables {
+			if isViewMatched(views, ts.IsView) {
+				tables = append(tables, ts.Name)
+			}
+		}
+	} else {
+		tableInfos, err := GetTableInfos(c)
+		if err != nil {
+			return nil, errors.WithMessage(err, "GetTableInfos err")
+		}
+		for _, ti := range tableInfos {
+			if isViewMatched(views, ti.Type == TableTypeView) {
+				tables = append(tables, ti.Name)
+			}
+		}
+	}
+
+	return matchTables(tables, includes, excludes)
+}
+
+// LoadSnapshot loads the schema snapshot from the json or yaml file.
+func LoadSnapshot(filePath string) (*Snapshot, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, errors.WithMessage(err, "os.ReadFile err")
+	}
+
+	// decode yaml into values to use the json tags
+	values, err := unmarshalConfigValues(content, GetConfigFormat(filePath))
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(values)
+	if err != nil {
+		return nil, errors.WithMessage(err, "json.Marshal err")
+	}
+
+	snapshot := &Snapshot{}
+	if err = json.Unmarshal(b, snapshot); err != nil {
+		return nil, errors.WithMessage(err, "json.Unmarshal err")
+	}
+	if snapshot.Version < 1 || snapshot.Version > SchemaVersion {
+		return nil, errors.New("unsupported snapshot version: " + strconv.Itoa(snapshot.Version))
+	}
+
+	return snapshot, nil
+}
+
+// getTableSchema returns the schema of table from the schema file or snapshot file if it is configured,
+// otherwise from the information schema of mysql.
+func getTableSchema(cc *CmdConfig) (*TableSchema, error) {
+	if cc.SnapshotFile != "" {
+		return getSnapshotTableSchema(cc)
+	}
+	if cc.SchemaFile == "" {
+		ts, err := DescribeTable(cc)
+		if err != nil {
+			return nil, errors.WithMessage(err, "DescribeTable err")
+		}
+		return ts, nil
+	}
+
+	schema, err := LoadSchema(cc.SchemaFile)
+	if err != nil {
+		return nil, errors.WithMessage(err, "LoadSchema err")
+	}
+	if cc.Table == "" {
+		cc.Table = schema.Table
+	} else if cc.Table != schema.Table {
+		return nil, errors.Errorf("table %s is not found in schema file, the table of schema is %s", cc.Table, schema.Table)
+	}
+
+	ts := &TableSchema{
+		Name: schema.Table, Comment: schema.Comment, Columns: schema.Columns, Indexes: schema.Indexes,
+		Checks: schema.Checks, IsView: schema.IsView, Definition: schema.Definition,
+	}
+	prepareColumnInfos(cc, ts)
+
+	return ts, nil
+}
+
+// getTableSchemas returns the schemas of tables in the order of tables from the snapshot file if it is configured,
+// otherwise from the information schema of mysql by one set of catalog queries.
+func getTableSchemas(cc *CmdConfig, tables []string) ([]*TableSchema, error) {
+	if cc.SchemaFile != "" {
+		if len(tables) != 1 {
+			return nil, errors.New("schema file can only be used with one table")
+		}
+		tc := *cc
+		tc.Table = tables[0]
+		ts, err := getTableSchema(&tc)
+		if err != nil {
+			return nil, err
+		}
+		return []*TableSchema{ts}, nil
+	}
+	if cc.SnapshotFile == "" {
+		tableSchemas, err := DescribeTables(cc, tables)
+		if err != nil {
+			return nil, errors.WithMessage(err, "DescribeTables err")
+		}
+		return tableSchemas, nil
+	}
+
+	snapshot, err := LoadSnapshot(cc.SnapshotFile)
+	if err != nil {
+		return nil, errors.WithMessage(err, "LoadSnapshot err")
+	}
+
+	tableSchemas := make([]*TableSchema, 0, len(tables))
+	for _, table := range tables {
+		ts := findTableSchema(snapshot, table)
+		if ts == nil {
+			return nil, errors.Errorf("table %s is not found in snapshot file", table)
+		}
+		prepareColumnInfos(cc, ts)
+		tableSchemas = append(tableSchemas, ts)
+	}
+
+	return tableSchemas, nil
+}
+
+// getSnapshotTableSchema returns the schema of table from the snapshot file.
+func getSnapshotTableSchema(cc *CmdConfig) (*TableSchema, error) {
+	snapshot, err := LoadSnapshot(cc.SnapshotFile)
+	if err != nil {
+		return nil, errors.WithMessage(err, "LoadSnapshot err")
+	}
+
+	ts := findTableSchema(snapshot, cc.Table)
+	if ts == nil {
+		return nil, errors.Errorf("table %s is not found in snapshot file", cc.Table)
+	}
+	prepareColumnInfos(cc, ts)
+
+	return ts, nil
+}
+
+// findTableSchema finds the schema of table in the snapshot.
+func findTableSchema(snapshot *Snapshot, table string) *TableSchema {
+	for _, ts := range snapshot.Tables {
+		if ts.Name == table {
+			return ts
+		}
+	}
+
+	return nil
+}
+
+// prepareColumnInfos fills the indexes of columns loaded from the file and applies the command config.
+func prepareColumnInfos(cc *CmdConfig, ts *TableSchema) {
+	ts.Indexes = sortIndexInfos(ts.Indexes)
+	markCompositeIndexes(ts.Indexes)
+	for _, ci := range ts.Columns {
+		ci.Indexes, ci.UniqueIndexes = getColumnIndexInfos(ts.Indexes, ci.Name)
+		if cc.DisableUnsigned {
+			ci.IsUnsigned = false
+		}
+	}
+}
+
+// isViewMatched reports whether the table or view is matched by the views mode.
+func isViewMatched(views string, isView bool) bool {
+	switch views {
+	case ViewsExclude:
+		return !isView
+	case ViewsOnly:
+		return isView
+	default:
+		return true
+	}
+}
+
+// matchTables returns the tables matched by the include patterns and not matched by the exclude patterns.
+func matchTables(tables, includes, excludes []string) ([]string, error) {
+	matched := make([]string, 0, len(tables))
+	for _, table := range tables {
+		included, err := matchPatterns(table, includes)
+		if err != nil {
+			return nil, err
+		}
+		excluded, err := matchPatterns(table, excludes)
+		if err != nil {
+			return nil, err
+		}
+		if (len(includes) == 0 || included) && !excluded {
+			matched = append(matched, table)
+		}
+	}
+
+	return matched, nil
+}
+
+// matchPatterns reports whether the name matches any of the shell patterns.
+func matchPatterns(name string, patterns []string) (bool, error) {
+	for _, pattern := range patterns {
+		ok, err := path.Match(pattern, name)
+		if err != nil {
+			return false, errors.WithMessage(err, "invalid table pattern: "+pattern)
+		}
+		if ok {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
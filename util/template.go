@@ -3,8 +3,10 @@ package util
 import (
 	"bytes"
 	_ "embed"
+	"fmt"
 	"go/format"
 	"log"
+	"sort"
 	"strings"
 	"text/template"
 
@@ -21,6 +23,15 @@ var (
 	beegoTplName  = "beego"
 	gormV2TplName = "gormV2"
 
+	// importPaths maps the package names of the field types to the import paths.
+	importPaths = map[string]string{
+		"time":        "time",
+		"sql":         "database/sql",
+		"null":        "gopkg.in/guregu/null.v4",
+		"gorm":        "gorm.io/gorm",
+		"soft_delete": "gorm.io/plugin/soft_delete",
+	}
+
 	//go:embed tpl/out.tpl
 	outTpl string
 	//go:embed tpl/gorm.tpl
@@ -35,24 +46,29 @@ var (
 
 func init() {
 	var err error
-	generator, err = template.New(outTplName).Parse(outTpl)
+	generator, err = template.New(outTplName).Funcs(
+		template.FuncMap{"join": strings.Join, "commentLines": commentLines, "singleLine": singleLine}).Parse(outTpl)
 	if err != nil {
 		log.Fatalln(color.Red.Render("parse out.tpl err:", err))
 	}
-	generator, err = generator.New(gormTplName).Parse(gormTpl)
+	generator, err = generator.New(gormTplName).Funcs(
+		template.FuncMap{"gormDefault": getGormDefault, "gormComment": getGormComment}).Parse(gormTpl)
 	if err != nil {
 		log.Fatalln(color.Red.Render("parse gorm.tpl err:", err))
 	}
-	generator, err = generator.New(xormTplName).Parse(xormTpl)
+	generator, err = generator.New(xormTplName).Funcs(
+		template.FuncMap{"xormDefault": getXormDefault, "xormComment": getXormComment}).Parse(xormTpl)
 	if err != nil {
 		log.Fatalln(color.Red.Render("parse xorm.tpl err:", err))
 	}
-	generator, err = generator.New(beegoTplName).Funcs(
-		template.FuncMap{"getBeegoType": getBeegoType}).Parse(beegoTpl)
+	generator, err = generator.New(beegoTplName).Funcs(template.FuncMap{
+		"getBeegoType": getBeegoType, "beegoDefault": getBeegoDefault, "beegoComment": getBeegoComment,
+	}).Parse(beegoTpl)
 	if err != nil {
 		log.Fatalln(color.Red.Render("parse beego.tpl err:", err))
 	}
-	generator, err = generator.New(gormV2TplName).Parse(gormV2Tpl)
+	generator, err = generator.New(gormV2TplName).Funcs(
+		template.FuncMap{"gormV2Default": getGormV2Default, "gormV2Comment": getGormV2Comment}).Parse(gormV2Tpl)
 	if err != nil {
 		log.Fatalln(color.Red.Render("parse gormv2.tpl err:", err))
 	}
@@ -64,34 +80,36 @@ func generateCode(cc *CmdConfig, fields []*StructField) (string, error) {
 	err := generator.ExecuteTemplate(buffer, outTplName, struct {
 		Table              string
 		TableComment       string
+		TableChecks        []*CheckInfo
+		IsView             bool
+		ViewDefinition     []string
 		PackageName        string
 		StructName         string
 		ShortStructName    string
 		StructFields       []*StructField
-		TableIndexes       []string
-		TableUniques       []string
+		TableIndexes       []*TableIndex
+		TableUniques       []*TableIndex
 		EnableFieldComment bool
-		NeedImport         bool
-		EnableGoTime       bool
-		EnableSQLNull      bool
-		EnableGureguNull   bool
+		Imports            []string
+		EnableConstructor  bool
 		EnableTableName    bool
 		EnableTableIndex   bool
 		EnableTableUnique  bool
 	}{
 		Table:              cc.Table,
 		TableComment:       cc.TableComment,
+		TableChecks:        cc.TableChecks,
+		IsView:             cc.IsView,
+		ViewDefinition:     splitLines(cc.ViewDefinition),
 		PackageName:        cc.PackageName,
 		StructName:         cc.StructName,
 		ShortStructName:    strings.ToLower(cc.StructName[0:1]),
 		StructFields:       fields,
-		TableIndexes:       uniqueStrings(cc.TableIndexes),
-		TableUniques:       uniqueStrings(cc.TableUniques),
+		TableIndexes:       uniqueTableIndexes(cc.TableIndexes),
+		TableUniques:       uniqueTableIndexes(cc.TableUniques),
 		EnableFieldComment: cc.EnableFieldComment,
-		NeedImport:         cc.EnableGoTime || cc.EnableSQLNull || cc.EnableGureguNull,
-		EnableGoTime:       cc.EnableGoTime,
-		EnableSQLNull:      cc.EnableSQLNull,
-		EnableGureguNull:   cc.EnableGureguNull,
+		Imports:            getImports(fields, getImportPaths(&cc.BaseModel)),
+		EnableConstructor:  cc.EnableConstructor && !cc.IsView,
 		EnableTableName:    cc.EnableGormTag || cc.EnableXormTag || cc.EnableBeegoTag || cc.EnableGoroseTag || cc.EnableGormV2Tag,
 		EnableTableIndex:   cc.EnableBeegoTag && len(cc.TableIndexes) != 0,
 		EnableTableUnique:  cc.EnableBeegoTag && len(cc.TableUniques) != 0,
@@ -108,28 +126,155 @@ func generateCode(cc *CmdConfig, fields []*StructField) (string, error) {
 	return string(code[:len(code)-1]), nil
 }
 
-// generateTag generates the tag string by column information and tag name.
-func generateTag(ci *ColumnInfo, tag string) string {
+// generateTag generates the tag string by column information, tag key and template name,
+// the tag value is quoted so that it can be parsed by reflect.StructTag.Lookup.
+func generateTag(ci *ColumnInfo, key, tag string) string {
 	buffer := &bytes.Buffer{}
 	err := generator.ExecuteTemplate(buffer, tag, ci)
 	if err != nil {
 		// err just print
-		color.Red.Printf("generateTag err: %v, tag: %s, column: %+v\n", err, tag, *ci)
+		printWarning("generateTag err: %v, tag: %s, column: %+v", err, tag, *ci)
 		return ""
 	}
 
-	return strings.TrimSpace(buffer.String())
+	return fmt.Sprintf("%s:%q", key, strings.TrimSpace(buffer.String()))
+}
+
+// customTag represents the parsed custom tag.
+type customTag struct {
+	key string
+	tpl *template.Template
+}
+
+// parseCustomTags parses the value templates of the custom tags.
+func parseCustomTags(cts []CustomTagConfig) ([]*customTag, error) {
+	funcs := template.FuncMap{
+		"snake":  func(s string) string { return convertCase(s, NamingSnake) },
+		"camel":  func(s string) string { return convertCase(s, NamingCamel) },
+		"pascal": func(s string) string { return convertCase(s, NamingPascal) },
+		"kebab":  func(s string) string { return convertCase(s, NamingKebab) },
+	}
+
+	tags := make([]*customTag, 0, len(cts))
+	for _, ct := range cts {
+		if ct.Key == "" {
+			return nil, errors.New("custom tag key is empty")
+		}
+		if !isValidTagKey(ct.Key) {
+			return nil, errors.New("custom tag key contains space, quote, colon or control characters: " + ct.Key)
+		}
+
+		tpl, err := template.New(ct.Key).Funcs(funcs).Parse(ct.Value)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "parse custom tag %s err", ct.Key)
+		}
+		tags = append(tags, &customTag{key: ct.Key, tpl: tpl})
+	}
+
+	return tags, nil
+}
+
+// isValidTagKey reports whether the key can be parsed by reflect.StructTag.Lookup and written in the raw string literal.
+func isValidTagKey(key string) bool {
+	for _, r := range key {
+		if r <= ' ' || r == ':' || r == '"' || r == '`' || r == 0x7f {
+			return false
+		}
+	}
+
+	return true
+}
+
+// generateCustomTag generates the custom tag string by column information.
+func generateCustomTag(ci *ColumnInfo, ct *customTag) string {
+	buffer := &bytes.Buffer{}
+	err := ct.tpl.Execute(buffer, ci)
+	if err != nil {
+		// err just print
+		printWarning("generateCustomTag err: %v, tag: %s, column: %+v", err, ct.key, *ci)
+		return ""
+	}
+
+	value := strings.TrimSpace(buffer.String())
+	if value == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%s:%q", ct.key, value)
+}
+
+// getImportPaths returns the import paths keyed by the package names, including the import of base model.
+func getImportPaths(bc *BaseModelConfig) map[string]string {
+	pkg, _ := splitQualifiedName(bc.Name)
+	if pkg == "" || bc.Import == "" {
+		return importPaths
+	}
+
+	paths := make(map[string]string, len(importPaths)+1)
+	for k, v := range importPaths {
+		paths[k] = v
+	}
+	paths[pkg] = bc.Import
+
+	return paths
+}
+
+// getImports returns the import paths of the field types, the standard packages are separated
+// from the third-party packages by an empty path.
+func getImports(fields []*StructField, paths map[string]string) []string {
+	var std, third []string
+	for _, field := range fields {
+		pkg := strings.TrimLeft(field.Type, "*[]")
+		if i := strings.Index(pkg, "."); i > 0 {
+			pkg = pkg[:i]
+		} else {
+			continue
+		}
+
+		path, ok := paths[pkg]
+		if !ok {
+			continue
+		}
+		if strings.Contains(path, ".") {
+			if !containsString(third, path) {
+				third = append(third, path)
+			}
+		} else if !containsString(std, path) {
+			std = append(std, path)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(third)
+
+	if len(std) > 0 && len(third) > 0 {
+		return append(append(std, ""), third...)
+	}
+
+	return append(std, third...)
+}
+
+// splitLines returns the non-empty lines of the string.
+func splitLines(s string) []string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimRight(line, " \t\r"); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
 }
 
-// uniqueStrings returns the unique string slice.
-func uniqueStrings(slice []string) []string {
-	result := make([]string, 0, len(slice))
+// uniqueTableIndexes returns the table indexes without the duplicate columns, the first index is kept.
+func uniqueTableIndexes(tableIndexes []*TableIndex) []*TableIndex {
+	result := make([]*TableIndex, 0, len(tableIndexes))
 	uniqueMap := make(map[string]struct{})
 
-	for _, value := range slice {
-		if _, ok := uniqueMap[value]; !ok {
-			uniqueMap[value] = struct{}{}
-			result = append(result, value)
+	for _, ti := range tableIndexes {
+		columns := strings.Join(ti.Columns, ",")
+		if _, ok := uniqueMap[columns]; !ok {
+			uniqueMap[columns] = struct{}{}
+			result = append(result, ti)
 		}
 	}
 
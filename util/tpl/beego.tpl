@@ -1,8 +1,8 @@
-orm:"
 {{- if .IsPrimaryKey }}pk;{{ end -}}
 {{- if .IsAutoIncrement }}auto;{{ end -}}
 column({{ .Name }}){{ getBeegoType . }}
 {{- if .IsNullable }};null{{ end -}}
-{{- if .Default }};default({{ .Default }}){{ end -}}
-{{- if .Comment }};description({{ .Comment }}){{ end -}}
-"
\ No newline at end of file
+{{- if eq .DataType "date" "datetime" "timestamp" }}
+    {{- if eq .Convention "created_at" }};auto_now_add{{ else if eq .Convention "updated_at" }};auto_now{{ end }}{{ end -}}
+{{- with beegoDefault . }};default({{ . }}){{ end -}}
+{{- with beegoComment .Comment }};description({{ . }}){{ end -}}
\ No newline at end of file
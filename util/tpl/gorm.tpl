@@ -1,4 +1,3 @@
-gorm:"
 {{- if .IsPrimaryKey }}primary_key;{{ end -}}
 column:{{ .Name }};type:{{ .Type }}{{ if .IsAutoIncrement }} auto_increment{{ end }}
 {{- if or .IsNullable .IsPrimaryKey | not }};not null{{ end -}}
@@ -6,6 +5,5 @@ column:{{ .Name }};type:{{ .Type }}{{ if .IsAutoIncrement }} auto_increment{{ en
     {{- if eq $i 0 }};index:{{ $v.Name }}{{ else }},{{ $v.Name }}{{ end }}{{ end -}}
 {{- range $i, $v := .UniqueIndexes }}
     {{- if eq $i 0 }};unique_index:{{ $v.Name }}{{ else }},{{ $v.Name }}{{ end }}{{ end -}}
-{{- if .Default }};default:'{{ .Default }}'{{ end -}}
-{{- if .Comment }};comment:'{{ .Comment }}'{{ end -}}
-"
\ No newline at end of file
+{{- with gormDefault . }};default:{{ . }}{{ end -}}
+{{- with gormComment .Comment }};comment:'{{ . }}'{{ end -}}
\ No newline at end of file
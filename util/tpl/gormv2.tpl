@@ -1,11 +1,16 @@
-gorm:"
+{{- if .IsReadOnly }}->;{{ end -}}
 {{- if .IsPrimaryKey }}primaryKey;{{ end -}}
 {{ if .IsAutoIncrement }}autoIncrement;{{ end }}column:{{ .Name }}{{ if not .IsPrimaryKey }};type:{{ .Type }}{{ end }}
 {{- if or .IsNullable .IsPrimaryKey | not }};not null{{ end -}}
-{{- range $i, $v := .Indexes }}
-    {{- if eq $i 0 }};index:{{ $v.Name }}{{ else }},{{ $v.Name }}{{ end }}{{ end -}}
-{{- range $i, $v := .UniqueIndexes }}
-    {{- if eq $i 0 }};uniqueIndex:{{ $v.Name }}{{ else }},{{ $v.Name }}{{ end }}{{ end -}}
-{{- if .Default }};default:{{ .Default }}{{ end -}}
-{{- if .Comment }};comment:{{ .Comment }}{{ end -}}
-"
\ No newline at end of file
+{{- range .Indexes }};index:{{ template "gormV2IndexOptions" . }}{{ end -}}
+{{- range .UniqueIndexes }};uniqueIndex:{{ template "gormV2IndexOptions" . }}{{ end -}}
+{{- with gormV2Default . }};default:{{ . }}{{ end -}}
+{{- if eq .Convention "created_at" }};autoCreateTime{{ else if eq .Convention "updated_at" }};autoUpdateTime
+    {{- else if eq .Convention "is_deleted" }};softDelete:flag{{ with .DeletedAtField }},DeletedAtField:{{ . }}{{ end }}{{ end -}}
+{{- with gormV2Comment .Comment }};comment:{{ . }}{{ end -}}
+
+{{- define "gormV2IndexOptions" }}{{ .Name }}
+    {{- if .IsComposite }},priority:{{ .Sequence }}{{ end -}}
+    {{- if eq .Type "FULLTEXT" "SPATIAL" }},class:{{ .Type }}{{ else if eq .Type "HASH" }},type:{{ .Type }}{{ end -}}
+    {{- if .SubPart }},length:{{ .SubPart }}{{ end -}}
+{{ end -}}
\ No newline at end of file
@@ -1,25 +1,58 @@
 package {{.PackageName}}
 
-{{ if .NeedImport }}
+{{ if .Imports }}
 import (
-	{{ if .EnableGureguNull }}
-		"gopkg.in/guregu/null.v4"
-	{{ else if .EnableSQLNull }}
-		"database/sql"
-	{{ else if .EnableGoTime }}
-		"time"
+	{{ range .Imports }}{{ if . }}"{{ . }}"{{ end }}
 	{{ end }}
 )
 {{ end }}
 
-// {{ .StructName }} {{ .TableComment }}
+// {{ .StructName }}
+{{- range $i, $v := commentLines .TableComment }}{{ if eq $i 0 }} {{ else }}
+// {{ end }}{{ $v }}{{ end }}
+{{- if .IsView }}
+//
+// {{ .StructName }} is read-only, which is mapped to the view {{ .Table }}.
+{{- if .ViewDefinition }}
+//
+// View definition:
+//
+{{- range .ViewDefinition }}
+//	{{ . }}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- if .TableChecks }}
+//
+// Check constraints:
+//
+{{- range .TableChecks }}
+//	{{ .Name }}: {{ singleLine .Clause }}
+{{- end }}
+{{- end }}
 type {{ .StructName }} struct {
 	{{ range .StructFields -}}
+		{{ $lines := commentLines .Comment -}}
+		{{ if and $.EnableFieldComment (gt (len $lines) 1) -}}
+			{{ range $lines }}// {{ . }}
+			{{ end -}}
+		{{ end -}}
 		{{ .Name }} {{ .Type }} {{ .Tag }}
-		{{- if and $.EnableFieldComment .Comment }}// {{ .Comment }}{{ end }}
+		{{- if and $.EnableFieldComment (eq (len $lines) 1) }}// {{ index $lines 0 }}{{ end }}
 	{{ end -}}
 }
 
+{{ if .EnableConstructor }}
+// New{{ .StructName }} returns a new {{ .StructName }} model with the default values of columns
+func New{{ .StructName }}() *{{ .StructName }} {
+	return &{{ .StructName }}{
+		{{ range .StructFields -}}
+			{{ if .DefaultValue }}{{ .Name }}: {{ .DefaultValue }},
+		{{ end }}{{ end -}}
+	}
+}
+{{ end }}
+
 {{ if .EnableTableName }}
 // TableName returns the table name of the {{ .StructName }} model
 func ({{ .ShortStructName }} *{{ .StructName }}) TableName() string {
@@ -32,8 +65,8 @@ func ({{ .ShortStructName }} *{{ .StructName }}) TableName() string {
 func ({{ .ShortStructName }} *{{ .StructName }}) TableIndex() [][]string {
 	return [][]string{
 		{{ range .TableIndexes -}}
-			{ {{ . }} },
-		{{ end }}
+			{ {{ join .Columns ", " }} }, // {{ .Name }}
+		{{ end -}}
 	}
 }
 {{ end }}
@@ -43,8 +76,8 @@ func ({{ .ShortStructName }} *{{ .StructName }}) TableIndex() [][]string {
 func ({{ .ShortStructName }} *{{ .StructName }}) TableUnique() [][]string {
 	return [][]string{
 		{{ range .TableUniques -}}
-			{ {{ . }} },
-		{{ end }}
+			{ {{ join .Columns ", " }} }, // {{ .Name }}
+		{{ end -}}
 	}
 }
 {{ end }}
\ No newline at end of file
@@ -1,10 +1,11 @@
-xorm:"
+{{- if .IsReadOnly }}<- {{ end -}}
 {{- if .IsPrimaryKey }}pk {{ end -}}
 {{- if .IsAutoIncrement }}autoincr {{ end -}}
 {{ .Type }} '{{ .Name }}'
 {{- if or .IsNullable .IsPrimaryKey | not }} notnull{{ end -}}
 {{- range $i, $v := .Indexes }} index({{ $v.Name }}){{ end -}}
 {{- range $i, $v := .UniqueIndexes }} unique({{ $v.Name }}){{ end -}}
-{{- if .Default }} default('{{ .Default }}'){{ end -}}
-{{- if .Comment }} comment('{{ .Comment }}'){{ end -}}
-"
\ No newline at end of file
+{{- if eq .Convention "created_at" }} created{{ else if eq .Convention "updated_at" }} updated
+    {{- else if eq .Convention "deleted_at" }} deleted{{ else if eq .Convention "version" }} version{{ end -}}
+{{- with xormDefault . }} default({{ . }}){{ end -}}
+{{- with xormComment .Comment }} comment('{{ . }}'){{ end -}}
\ No newline at end of file
@@ -2,9 +2,13 @@ package util
 
 import (
 	"fmt"
+	"os"
 	"strings"
+	"sync"
 	"unicode"
+	"unicode/utf8"
 
+	"github.com/gookit/color"
 	"github.com/pkg/errors"
 )
 
@@ -20,37 +24,157 @@ func ConvertTable(cc CmdConfig) (string, error) {
 	return generateCode(&cc, fields)
 }
 
+// ConvertTables converts the tables to golang model structures by command config with at most workers goroutines,
+// the schemas of all tables are fetched by one set of catalog queries, and the outputs are in the order of tables
+// regardless of the number of workers.
+func ConvertTables(cc CmdConfig, tables []string, workers int) ([]*TableOutput, error) {
+	defer CloseDB()
+
+	tableSchemas, err := getTableSchemas(&cc, tables)
+	if err != nil {
+		return nil, err
+	}
+
+	if workers < 1 {
+		workers = 1
+	}
+	outputs := make([]*TableOutput, len(tables))
+	errs := make([]error, len(tables))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers && i < len(tables); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range indexes {
+				tc := cc
+				tc.Table = tables[j]
+				fields, err := getFields(&tc, tableSchemas[j])
+				if err != nil {
+					errs[j] = err
+					continue
+				}
+				out, err := generateCode(&tc, fields)
+				if err != nil {
+					errs[j] = err
+					continue
+				}
+				outputs[j] = &TableOutput{Table: tables[j], Output: out}
+			}
+		}()
+	}
+	for i := range tables {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, errors.WithMessage(err, "table "+tables[i]+" err")
+		}
+	}
+
+	return outputs, nil
+}
+
 // GetFields gets golang structure fields converted by mysql table fields.
 // Note that after using this function, you need to call the util.CloseDB() function to close the database.
 func GetFields(cc *CmdConfig) ([]*StructField, error) {
+	ts, err := getTableSchema(cc)
+	if err != nil {
+		return nil, err
+	}
+
+	return getFields(cc, ts)
+}
+
+// getFields gets golang structure fields converted by the table schema.
+func getFields(cc *CmdConfig, ts *TableSchema) ([]*StructField, error) {
 	if cc.PackageName == "" {
 		cc.PackageName = "model"
 	}
+	cc.initialisms = newInitialisms(cc.EnableInitialism, cc.Initialisms)
 	if cc.StructName == "" {
-		cc.StructName = convertName(cc.Table, cc.EnableInitialism)
+		cc.StructName = convertName(getStructTableName(cc), cc.initialisms)
 	}
 
-	comment, err := getTableComment(cc)
-	if err != nil {
-		return nil, errors.WithMessage(err, "getTableComment err")
+	cc.TableComment = ts.Comment
+	cc.IsView, cc.ViewDefinition = ts.IsView, ""
+	tc := getTableColumns(cc, ts.Name)
+	cis := filterColumns(ts.Name, tc, ts.Columns)
+	fieldNames := getFieldNames(cis, tc.rename, cc.initialisms)
+	if ts.IsView {
+		if cc.EnableViewDefinition {
+			cc.ViewDefinition = ts.Definition
+		}
+		// the view is read-only and has no primary key
+		for _, ci := range cis {
+			ci.IsPrimaryKey, ci.IsAutoIncrement, ci.IsReadOnly = false, false, true
+		}
+	}
+	cc.TableChecks = ts.Checks
+	for _, ci := range cis {
+		if ci.Generated != "" {
+			// the generated column can not be written
+			ci.IsReadOnly = true
+		}
+		ci.Checks = getColumnCheckInfos(ts.Checks, ci.Name)
+	}
+	if cc.EnableGormV2Tag || cc.EnableXormTag || cc.EnableBeegoTag {
+		markConventionColumns(&cc.Conventions, cis, fieldNames)
 	}
-	cc.TableComment = comment
 
-	cis, err := getColumnInfos(cc)
+	customTags, err := parseCustomTags(cc.CustomTags)
 	if err != nil {
-		return nil, errors.WithMessage(err, "getColumnInfos err")
+		return nil, errors.WithMessage(err, "parseCustomTags err")
+	}
+
+	if cc.EnableGormTag || cc.EnableXormTag {
+		for _, name := range getMisorderedIndexes(ts) {
+			printWarning("the columns of composite index %s are not in the field order, "+
+				"which is used by gorm v1 and xorm to create the index", name)
+		}
 	}
 
+	singlePrimaryKey := countPrimaryKeys(cis) == 1
+
 	fields := make([]*StructField, 0, len(cis))
 	for i := range cis {
 		ci := cis[i]
 		var tags []string
 
+		fieldType := convertDataType(ci, cc)
+		if cc.EnableGormV2Tag && !cc.EnableGormTag {
+			if conventionType := getConventionType(ci, cis); conventionType != "" {
+				fieldType = conventionType
+			}
+		}
+
 		if cc.EnableJSONTag {
-			tags = append(tags, getJSONTag(ci))
+			tags = append(tags, getJSONTag(ci, &cc.JSONTag, fieldType))
 		}
 		if cc.EnableXMLTag {
-			tags = append(tags, getXMLTag(ci))
+			tags = append(tags, getXMLTag(ci, &cc.XMLTag))
+		}
+		if cc.EnableYAMLTag {
+			tags = append(tags, getYAMLTag(ci, &cc.YAMLTag))
+		}
+		if cc.EnableTOMLTag {
+			tags = append(tags, getTOMLTag(ci, &cc.TOMLTag))
+		}
+		if cc.EnableBSONTag {
+			tags = append(tags, getBSONTag(ci, &cc.BSONTag, singlePrimaryKey))
+		}
+		if cc.EnableMsgpackTag {
+			tags = append(tags, getMsgpackTag(ci, &cc.MsgpackTag))
+		}
+		if cc.EnableMapstructureTag {
+			tags = append(tags, getMapstructureTag(ci, &cc.MapstructureTag))
+		}
+		if cc.EnableFormTag {
+			tags = append(tags, getFormTag(ci, &cc.FormTag))
 		}
 		if cc.EnableGormTag {
 			tags = append(tags, getGormTag(ci))
@@ -67,18 +191,25 @@ func GetFields(cc *CmdConfig) ([]*StructField, error) {
 		if cc.EnableGormV2Tag && !cc.EnableGormTag {
 			tags = append(tags, getGormV2Tag(ci))
 		}
+		if cc.EnableValidateTag {
+			tags = append(tags, getValidateTag(ci, &cc.ValidateTag, fieldType))
+		}
+		for _, ct := range customTags {
+			tags = append(tags, generateCustomTag(ci, ct))
+		}
 
 		field := StructField{
-			Name:         convertName(ci.Name, cc.EnableInitialism),
-			Type:         convertDataType(ci, cc),
-			Comment:      ci.Comment,
+			Name:         fieldNames[ci.Name],
+			Type:         fieldType,
+			Comment:      getFieldComment(ci),
 			RawName:      ci.Name,
 			Default:      ci.Default,
+			DefaultValue: getGoDefault(ci, fieldType),
 			IsPrimaryKey: ci.IsPrimaryKey,
 			IsNullable:   ci.IsNullable,
 		}
 		if len(tags) > 0 {
-			field.Tag = fmt.Sprintf("`%s`", strings.Join(removeEmpty(tags), " "))
+			field.Tag = formatStructTag(removeEmpty(tags))
 		}
 		if field.Type == GoTime {
 			cc.EnableGoTime = true
@@ -86,17 +217,74 @@ func GetFields(cc *CmdConfig) ([]*StructField, error) {
 		fields = append(fields, &field)
 	}
 
+	reserved := reservedFieldNames
+	if cc.BaseModel.Name != "" {
+		var reason string
+		if fields, reason = embedBaseModel(&cc.BaseModel, fields); reason != "" {
+			if cc.Verbose {
+				printVerbose("table %s does not embed the base model %s: %s", cc.Table, cc.BaseModel.Name, reason)
+			}
+		} else {
+			_, name := splitQualifiedName(cc.BaseModel.Name)
+			reserved = append([]string{name}, reservedFieldNames...)
+		}
+	}
+
+	named := make([]*StructField, 0, len(fields))
+	names := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if !field.IsEmbedded {
+			named = append(named, field)
+			names = append(names, field.Name)
+		}
+	}
+	for i, name := range disambiguateNames(names, reserved...) {
+		if name != named[i].Name {
+			printWarning("field name %s of column %s is renamed to %s to avoid collision",
+				named[i].Name, named[i].RawName, name)
+			named[i].Name = name
+		}
+		// the indexes refer to the disambiguated field names
+		fieldNames[named[i].RawName] = name
+	}
+	if cc.EnableBeegoTag {
+		cc.TableIndexes, cc.TableUniques = getTableIndexes(ts.Indexes, fieldNames)
+	}
+
 	return fields, nil
 }
 
+// getFieldComment returns the comment of the field, the generation expression of the generated column is appended.
+func getFieldComment(ci *ColumnInfo) string {
+	if ci.Generated == "" || ci.GenerationExpression == "" {
+		return ci.Comment
+	}
+
+	generated := fmt.Sprintf("generated always as (%s) %s", trimCheckParens(ci.GenerationExpression), strings.ToLower(ci.Generated))
+	if ci.Comment == "" {
+		return generated
+	}
+
+	return ci.Comment + ", " + generated
+}
+
+// getColumnCheckInfos returns the check constraints only referencing the column.
+func getColumnCheckInfos(checkInfos []*CheckInfo, columnName string) []*CheckInfo {
+	var columnChecks []*CheckInfo
+	for _, checkInfo := range checkInfos {
+		if columns := getCheckColumns(checkInfo.Clause); len(columns) == 1 && columns[0] == columnName {
+			columnChecks = append(columnChecks, checkInfo)
+		}
+	}
+
+	return columnChecks
+}
+
 // convertDataType converts the mysql data type to golang data type.
 func convertDataType(ci *ColumnInfo, cc *CmdConfig) string {
 	switch ci.DataType {
 	case "tinyint", "smallint", "mediumint":
-		isBool := false
-		if strings.Contains(ci.Type, "tinyint(1)") {
-			isBool = true
-		}
+		isBool := isBoolColumn(ci)
 		if ci.IsNullable {
 			if cc.EnableGureguNull {
 				if isBool {
@@ -187,64 +375,219 @@ func convertDataType(ci *ColumnInfo, cc *CmdConfig) string {
 	}
 }
 
-// convertName converts the name to camel case name.
-func convertName(name string, enableInitialism ...bool) string {
+// convertName converts the name to exported golang identifier in camel case,
+// such as user_name to UserName, 2fa_enabled to X2faEnabled and order-id to OrderId.
+// The initialisms map the upper case initialisms to their canonical forms, nil means disabled.
+func convertName(name string, initialisms map[string]string) string {
 	if name == "" {
 		return ""
 	}
 
-	enable := false
-	if len(enableInitialism) != 0 {
-		enable = enableInitialism[0]
+	var cn strings.Builder
+	for _, seg := range splitNameSegments(name) {
+		if seg.escaped {
+			cn.WriteString(seg.text)
+			continue
+		}
+
+		if initialism, ok := initialisms[strings.ToUpper(seg.text)]; ok {
+			cn.WriteString(initialism)
+		} else {
+			cn.WriteString(titleWord(seg.text))
+		}
+	}
+
+	result := []rune(cn.String())
+	if len(result) > 0 && unicode.IsLower(result[0]) {
+		// the identifier starts with mixed case initialism, such as iOS
+		result[0] = unicode.ToUpper(result[0])
+	}
+	if len(result) == 0 || !unicode.IsUpper(result[0]) {
+		// the identifier starts with digit or letter without case, such as 2fa and 用户
+		result = append([]rune(exportedPrefix), result...)
+	}
+
+	return string(result)
+}
+
+// newInitialisms returns the initialisms used by convertName, the custom initialisms
+// will be added to the common initialisms, and those starting with - will be removed,
+// such as SKU, OAuth and -ID, lower case initialism will be treated as upper case. It returns nil if the initialism is disabled.
+func newInitialisms(enable bool, custom []string) map[string]string {
+	if !enable {
+		return nil
 	}
 
-	var cn string
-	s := strings.Split(name, "_")
+	initialisms := make(map[string]string, len(commonInitialisms)+len(custom))
+	for initialism := range commonInitialisms {
+		initialisms[initialism] = initialism
+	}
+	for _, initialism := range custom {
+		initialism = strings.TrimSpace(initialism)
+		if strings.HasPrefix(initialism, "-") {
+			delete(initialisms, strings.ToUpper(initialism[1:]))
+		} else if initialism != "" {
+			upper := strings.ToUpper(initialism)
+			if initialism == strings.ToLower(initialism) {
+				// lower case initialism means upper case, such as sku
+				initialism = upper
+			}
+			initialisms[upper] = initialism
+		}
+	}
 
-	for _, v := range s {
-		upperV := strings.ToUpper(v)
-		if _, ok := commonInitialisms[upperV]; ok && enable {
-			cn += upperV
+	return initialisms
+}
+
+// nameSegment represents the segment of the name split by separators.
+type nameSegment struct {
+	text    string
+	escaped bool
+}
+
+// splitNameSegments splits the name into segments by ascii separators, such as _ - . and space,
+// latin letters with diacritics will be transliterated and other non-ascii symbols will be escaped.
+func splitNameSegments(name string) []nameSegment {
+	var (
+		segments []nameSegment
+		seg      strings.Builder
+	)
+	flush := func() {
+		if seg.Len() > 0 {
+			segments = append(segments, nameSegment{text: seg.String()})
+			seg.Reset()
+		}
+	}
+
+	for _, r := range name {
+		if t, ok := transliterations[r]; ok {
+			seg.WriteString(t)
+		} else if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			seg.WriteRune(r)
+		} else if r < utf8.RuneSelf || unicode.IsSpace(r) || r == utf8.RuneError {
+			flush()
 		} else {
-			if runesV := []rune(v); len(runesV) > 0 {
-				for i, r := range runesV {
-					if i == 0 {
-						runesV[i] = unicode.ToUpper(r)
-					} else {
-						runesV[i] = unicode.ToLower(r)
-					}
+			flush()
+			segments = append(segments, nameSegment{text: fmt.Sprintf("U%04X", r), escaped: true})
+		}
+	}
+	flush()
+
+	return segments
+}
+
+// disambiguateNames makes the names unique case-insensitively by appending the smallest
+// numeric suffix starting from 2, the former name keeps unchanged and the reserved names can not be used.
+func disambiguateNames(names []string, reserved ...string) []string {
+	taken := make(map[string]struct{}, len(names)+len(reserved))
+	for _, name := range reserved {
+		taken[strings.ToLower(name)] = struct{}{}
+	}
+	originals := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		originals[strings.ToLower(name)] = struct{}{}
+	}
+
+	result := make([]string, len(names))
+	for i, name := range names {
+		lowerName := strings.ToLower(name)
+		if _, ok := taken[lowerName]; ok {
+			for n := 2; ; n++ {
+				candidate := fmt.Sprintf("%s%d", name, n)
+				lowerCandidate := strings.ToLower(candidate)
+				_, isTaken := taken[lowerCandidate]
+				_, isOriginal := originals[lowerCandidate]
+				if !isTaken && !isOriginal {
+					name, lowerName = candidate, lowerCandidate
+					break
 				}
-				cn += string(runesV)
 			}
 		}
+		taken[lowerName] = struct{}{}
+		result[i] = name
 	}
 
-	return cn
+	return result
 }
 
 // getJSONTag returns the tag string of json.
-func getJSONTag(ci *ColumnInfo) string {
-	return fmt.Sprintf("json:%q", ci.Name)
+func getJSONTag(ci *ColumnInfo, tc *TagConfig, fieldType string) string {
+	var options []string
+	if tc.StringBigint && ci.DataType == "bigint" && isIDColumn(ci) &&
+		(fieldType == GoInt64 || fieldType == GoUint64) {
+		options = append(options, "string")
+	}
+
+	return getNamedTag("json", ci, tc, options...)
 }
 
 // getXMLTag returns the tag string of xml.
-func getXMLTag(ci *ColumnInfo) string {
-	return fmt.Sprintf("xml:%q", ci.Name)
+func getXMLTag(ci *ColumnInfo, tc *TagConfig) string {
+	return getNamedTag("xml", ci, tc)
+}
+
+// getYAMLTag returns the tag string of yaml.
+func getYAMLTag(ci *ColumnInfo, tc *TagConfig) string {
+	return getNamedTag("yaml", ci, tc)
+}
+
+// getTOMLTag returns the tag string of toml.
+func getTOMLTag(ci *ColumnInfo, tc *TagConfig) string {
+	return getNamedTag("toml", ci, tc)
+}
+
+// getBSONTag returns the tag string of bson, the single primary key will be mapped to _id.
+func getBSONTag(ci *ColumnInfo, tc *TagConfig, singlePrimaryKey bool) string {
+	if ci.IsPrimaryKey && singlePrimaryKey && !containsString(tc.HiddenColumns, ci.Name) {
+		return fmt.Sprintf("bson:%q", "_id,omitempty")
+	}
+
+	return getNamedTag("bson", ci, tc)
+}
+
+// getMsgpackTag returns the tag string of msgpack.
+func getMsgpackTag(ci *ColumnInfo, tc *TagConfig) string {
+	return getNamedTag("msgpack", ci, tc)
+}
+
+// getMapstructureTag returns the tag string of mapstructure.
+func getMapstructureTag(ci *ColumnInfo, tc *TagConfig) string {
+	return getNamedTag("mapstructure", ci, tc)
+}
+
+// getFormTag returns the tag string of form.
+func getFormTag(ci *ColumnInfo, tc *TagConfig) string {
+	return getNamedTag("form", ci, tc)
+}
+
+// getNamedTag returns the tag string whose name is converted by the naming strategy of tag config.
+func getNamedTag(key string, ci *ColumnInfo, tc *TagConfig, options ...string) string {
+	if containsString(tc.HiddenColumns, ci.Name) {
+		return fmt.Sprintf("%s:%q", key, "-")
+	}
+
+	values := []string{convertCase(ci.Name, tc.Naming)}
+	if tc.OmitEmpty && (ci.IsNullable || ci.Default != "") {
+		values = append(values, "omitempty")
+	}
+	values = append(values, options...)
+
+	return fmt.Sprintf("%s:%q", key, strings.Join(values, ","))
 }
 
 // getGormTag returns the tag string of gorm.
 func getGormTag(ci *ColumnInfo) string {
-	return generateTag(ci, gormTplName)
+	return generateTag(ci, "gorm", gormTplName)
 }
 
 // getXormTag returns the tag string of xorm.
 func getXormTag(ci *ColumnInfo) string {
-	return generateTag(ci, xormTplName)
+	return generateTag(ci, "xorm", xormTplName)
 }
 
 // getBeegoTag returns the tag string of beego orm.
 func getBeegoTag(ci *ColumnInfo) string {
-	return generateTag(ci, beegoTplName)
+	return generateTag(ci, "orm", beegoTplName)
 }
 
 // getGoroseTag returns the tag string of gorose.
@@ -254,7 +597,7 @@ func getGoroseTag(ci *ColumnInfo) string {
 
 // getGormV2Tag returns the tag string of gorm v2.
 func getGormV2Tag(ci *ColumnInfo) string {
-	return generateTag(ci, gormV2TplName)
+	return generateTag(ci, "gorm", gormV2TplName)
 }
 
 // getBeegoType returns the type tag string of beego orm.
@@ -284,6 +627,50 @@ func getBeegoType(ci *ColumnInfo) string {
 	}
 }
 
+// isBoolColumn reports whether the column is a boolean column, such as tinyint(1).
+func isBoolColumn(ci *ColumnInfo) bool {
+	return strings.Contains(ci.Type, "tinyint(1)")
+}
+
+// countPrimaryKeys returns the number of primary key columns.
+func countPrimaryKeys(cis []*ColumnInfo) int {
+	count := 0
+	for _, ci := range cis {
+		if ci.IsPrimaryKey {
+			count++
+		}
+	}
+
+	return count
+}
+
+// isIDColumn reports whether the column is an id column, such as id and user_id.
+func isIDColumn(ci *ColumnInfo) bool {
+	name := strings.ToLower(ci.Name)
+	return ci.IsPrimaryKey || name == "id" || strings.HasSuffix(name, "_id")
+}
+
+// printWarning prints the yellow warning to stderr, which keeps the generated code on stdout clean.
+func printWarning(format string, a ...interface{}) {
+	fmt.Fprintln(os.Stderr, color.Yellow.Sprintf(format, a...))
+}
+
+// printVerbose prints the gray verbose message to stderr.
+func printVerbose(format string, a ...interface{}) {
+	fmt.Fprintln(os.Stderr, color.Gray.Sprintf(format, a...))
+}
+
+// containsString reports whether the string is in the slice.
+func containsString(slice []string, s string) bool {
+	for _, v := range slice {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
 // removeEmpty remove empty fields.
 func removeEmpty(slice []string) []string {
 	result := make([]string, 0, len(slice))
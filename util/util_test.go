@@ -1,11 +1,25 @@
 package util
 
 import (
+	"bytes"
+	"crypto/aes"
+	"encoding/binary"
 	"encoding/json"
+	"flag"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math/rand"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 )
 
+var update = flag.Bool("update", false, "update the golden files of testdata")
+
 func TestConvertTable(t *testing.T) {
 	config := CmdConfig{
 		DBConfig: DBConfig{
@@ -94,16 +108,29 @@ func TestConvertName(t *testing.T) {
 		{"test", true, "Test"},
 		{"user_name", true, "UserName"},
 		{"USER_PASSWORD", true, "UserPassword"},
-		{"测试", true, "测试"},
-		{"用户_名称", true, "用户名称"},
+		{"测试", true, "X测试"},
+		{"用户_名称", true, "X用户名称"},
 		{"user_name", false, "UserName"},
 		{"USER_PASSWORD", false, "UserPassword"},
-		{"测试", false, "测试"},
-		{"用户_名称", false, "用户名称"},
+		{"测试", false, "X测试"},
+		{"用户_名称", false, "X用户名称"},
+		{"2fa_enabled", true, "X2faEnabled"},
+		{"type", true, "Type"},
+		{"order-id", true, "OrderID"},
+		{"user.name", true, "UserName"},
+		{"first name", false, "FirstName"},
+		{"  padded__name  ", false, "PaddedName"},
+		{"café_crème", false, "CafeCreme"},
+		{"straße", false, "Strasse"},
+		{"user_名称", false, "User名称"},
+		{"price_€", false, "PriceU20AC"},
+		{"___", false, "X"},
+		{"$", false, "X"},
+		{"_id", true, "ID"},
 	}
 
 	for _, c := range cases {
-		output := convertName(c.input, c.enable)
+		output := convertName(c.input, newInitialisms(c.enable, nil))
 		if output != c.expectation {
 			t.Errorf("convertName failed, input:%s, enable:%v, expectation:%s, output:%s",
 				c.input, c.enable, c.expectation, output)
@@ -111,17 +138,105 @@ func TestConvertName(t *testing.T) {
 	}
 }
 
+func TestNewInitialisms(t *testing.T) {
+	cases := []struct {
+		input       string
+		custom      []string
+		expectation string
+	}{
+		{"product_sku", nil, "ProductSku"},
+		{"product_sku", []string{"SKU"}, "ProductSKU"},
+		{"oauth_token", []string{"OAuth"}, "OAuthToken"},
+		{"ios_version", []string{"iOS"}, "IOSVersion"},
+		{"min_ios_version", []string{"iOS"}, "MiniOSVersion"},
+		{"user_id", []string{"-ID"}, "UserId"},
+		{"user_id", []string{"-id", "kyc", "OTP"}, "UserId"},
+		{"kyc_otp", []string{"-id", "kyc", "OTP"}, "KYCOTP"},
+	}
+
+	for _, c := range cases {
+		output := convertName(c.input, newInitialisms(true, c.custom))
+		if output != c.expectation {
+			t.Errorf("convertName with initialisms failed, input:%s, custom:%v, expectation:%s, output:%s",
+				c.input, c.custom, c.expectation, output)
+		}
+	}
+
+	if newInitialisms(false, []string{"SKU"}) != nil {
+		t.Error("newInitialisms should return nil when initialism is disabled")
+	}
+	if initialisms := newInitialisms(true, []string{"-ID"}); len(commonInitialisms) == len(initialisms) {
+		t.Error("newInitialisms should remove the initialism")
+	}
+	if _, ok := commonInitialisms["ID"]; !ok {
+		t.Error("newInitialisms should not modify the common initialisms")
+	}
+}
+
+func TestDisambiguateNames(t *testing.T) {
+	cases := []struct {
+		input       []string
+		reserved    []string
+		expectation []string
+	}{
+		{[]string{"ID", "Name"}, nil, []string{"ID", "Name"}},
+		{[]string{"UserID", "Userid"}, nil, []string{"UserID", "Userid2"}},
+		{[]string{"X", "X", "X"}, nil, []string{"X", "X2", "X3"}},
+		{[]string{"A", "A", "A2"}, nil, []string{"A", "A3", "A2"}},
+		{[]string{"TableName", "Name"}, reservedFieldNames, []string{"TableName2", "Name"}},
+	}
+
+	for _, c := range cases {
+		output := disambiguateNames(c.input, c.reserved...)
+		if !reflect.DeepEqual(output, c.expectation) {
+			t.Errorf("disambiguateNames failed, input:%v, expectation:%v, output:%v",
+				c.input, c.expectation, output)
+		}
+	}
+}
+
 func TestGetJSONTag(t *testing.T) {
 	cases := []struct {
 		ci          ColumnInfo
+		tc          TagConfig
+		fieldType   string
 		expectation string
 	}{
-		{ColumnInfo{Name: "id"}, "json:\"id\""},
-		{ColumnInfo{Name: "name"}, "json:\"name\""},
+		{ColumnInfo{Name: "id"}, TagConfig{}, "", "json:\"id\""},
+		{ColumnInfo{Name: "name"}, TagConfig{}, "", "json:\"name\""},
+		{ColumnInfo{Name: "user_name"}, TagConfig{Naming: NamingCamel}, "", "json:\"userName\""},
+		{ColumnInfo{Name: "user_name"}, TagConfig{Naming: NamingPascal}, "", "json:\"UserName\""},
+		{ColumnInfo{Name: "user_name"}, TagConfig{Naming: NamingKebab}, "", "json:\"user-name\""},
+		{ColumnInfo{Name: "userName"}, TagConfig{Naming: NamingSnake}, "", "json:\"user_name\""},
+		{ColumnInfo{Name: "userName"}, TagConfig{Naming: NamingRaw}, "", "json:\"userName\""},
+		{
+			ColumnInfo{Name: "nick_name", IsNullable: true},
+			TagConfig{Naming: NamingCamel, OmitEmpty: true}, "", "json:\"nickName,omitempty\"",
+		},
+		{
+			ColumnInfo{Name: "status", Default: "1"},
+			TagConfig{OmitEmpty: true}, "", "json:\"status,omitempty\"",
+		},
+		{
+			ColumnInfo{Name: "name"},
+			TagConfig{OmitEmpty: true}, "", "json:\"name\"",
+		},
+		{
+			ColumnInfo{Name: "id", DataType: "bigint", IsPrimaryKey: true},
+			TagConfig{StringBigint: true}, GoUint64, "json:\"id,string\"",
+		},
+		{
+			ColumnInfo{Name: "user_id", DataType: "bigint", IsNullable: true},
+			TagConfig{StringBigint: true}, SQLNullInt64, "json:\"user_id\"",
+		},
+		{
+			ColumnInfo{Name: "password_hash"},
+			TagConfig{HiddenColumns: []string{"password_hash"}}, "", "json:\"-\"",
+		},
 	}
 
 	for _, c := range cases {
-		output := getJSONTag(&c.ci)
+		output := getJSONTag(&c.ci, &c.tc, c.fieldType)
 		if output != c.expectation {
 			t.Errorf("getJSONTag failed, expectation:%s, output:%s",
 				c.expectation, output)
@@ -132,14 +247,16 @@ func TestGetJSONTag(t *testing.T) {
 func TestGetXMLTag(t *testing.T) {
 	cases := []struct {
 		ci          ColumnInfo
+		tc          TagConfig
 		expectation string
 	}{
-		{ColumnInfo{Name: "id"}, "xml:\"id\""},
-		{ColumnInfo{Name: "name"}, "xml:\"name\""},
+		{ColumnInfo{Name: "id"}, TagConfig{}, "xml:\"id\""},
+		{ColumnInfo{Name: "name"}, TagConfig{}, "xml:\"name\""},
+		{ColumnInfo{Name: "user_name", IsNullable: true}, TagConfig{Naming: NamingPascal, OmitEmpty: true}, "xml:\"UserName,omitempty\""},
 	}
 
 	for _, c := range cases {
-		output := getXMLTag(&c.ci)
+		output := getXMLTag(&c.ci, &c.tc)
 		if output != c.expectation {
 			t.Errorf("getXMLTag failed, expectation:%s, output:%s",
 				c.expectation, output)
@@ -205,7 +322,7 @@ func TestGetGormV2Tag(t *testing.T) {
 				IsAutoIncrement: false, IsNullable: false, Default: "user", Comment: "用户名称",
 				Indexes: []*IndexInfo{{Name: "name_index"}, {Name: "name_email_index"}},
 			},
-			"gorm:\"column:name;type:varchar(255);not null;index:name_index,name_email_index;default:user;comment:用户名称\"",
+			"gorm:\"column:name;type:varchar(255);not null;index:name_index;index:name_email_index;default:'user';comment:用户名称\"",
 		},
 		{
 			ColumnInfo{
@@ -214,7 +331,20 @@ func TestGetGormV2Tag(t *testing.T) {
 				Indexes:       []*IndexInfo{{Name: "name_email_index"}},
 				UniqueIndexes: []*IndexInfo{{Name: "email_index"}},
 			},
-			"gorm:\"column:email;type:varchar(255);not null;index:name_email_index;uniqueIndex:email_index;default:email;comment:用户邮箱\"",
+			"gorm:\"column:email;type:varchar(255);not null;index:name_email_index;uniqueIndex:email_index;default:'email';comment:用户邮箱\"",
+		},
+		{
+			ColumnInfo{
+				Name: "title", Type: "varchar(255)", IsNullable: true,
+				Indexes: []*IndexInfo{
+					{Name: "idx_title", Type: "FULLTEXT"},
+					{Name: "idx_title_hash", Type: "HASH"},
+					{Name: "idx_user_title", Sequence: 2, Type: "BTREE", SubPart: 16, IsComposite: true},
+				},
+				UniqueIndexes: []*IndexInfo{{Name: "uniq_title_user", Sequence: 1, Type: "BTREE", SubPart: 32, IsComposite: true}},
+			},
+			"gorm:\"column:title;type:varchar(255);index:idx_title,class:FULLTEXT;index:idx_title_hash,type:HASH;" +
+				"index:idx_user_title,priority:2,length:16;uniqueIndex:uniq_title_user,priority:1,length:32\"",
 		},
 	}
 
@@ -373,21 +503,2161 @@ func TestGetBeegoType(t *testing.T) {
 	}
 }
 
-func TestUniqueStrings(t *testing.T) {
+func TestGenerateGolden(t *testing.T) {
+	indexInfos := []*IndexInfo{
+		{Name: "uniq_tenant_user", ColumnName: "tenant_id", Sequence: 1, IsUnique: true},
+		{Name: "uniq_tenant_user", ColumnName: "user_name", Sequence: 2, IsUnique: true},
+		{Name: "uniq_email", ColumnName: "email", Sequence: 1, IsUnique: true},
+		{Name: "idx_status_created", ColumnName: "status", Sequence: 1},
+		{Name: "idx_status_created", ColumnName: "created_at", Sequence: 2},
+		{Name: "idx_created_status", ColumnName: "created_at", Sequence: 1},
+		{Name: "idx_created_status", ColumnName: "status", Sequence: 2},
+		{Name: "idx_user_name", ColumnName: "user_name", Sequence: 1, Type: "BTREE", SubPart: 8},
+		{Name: "ft_email", ColumnName: "email", Sequence: 1, Type: "FULLTEXT"},
+	}
+	columnInfos := []*ColumnInfo{
+		{Name: "id", DataType: "bigint", Type: "bigint unsigned", Position: 1, IsPrimaryKey: true, IsAutoIncrement: true, IsUnsigned: true},
+		{Name: "tenant_id", DataType: "int", Type: "int", Position: 2},
+		{Name: "user_name", DataType: "varchar", Type: "varchar(32)", Length: 32, Position: 3},
+		{Name: "email", DataType: "varchar", Type: "varchar(64)", Length: 64, Position: 4},
+		{Name: "status", DataType: "tinyint", Type: "tinyint", Position: 5},
+		{Name: "created_at", DataType: "datetime", Type: "datetime", Position: 6},
+	}
+
+	for _, name := range []string{"beego", "gormv2", "xorm"} {
+		var golden string
+		for i := 0; i < 10; i++ {
+			// the order of the index infos loaded from the files is not guaranteed
+			shuffled := make([]*IndexInfo, len(indexInfos))
+			for j, k := range rand.Perm(len(indexInfos)) {
+				ii := *indexInfos[k]
+				shuffled[j] = &ii
+			}
+			cis := make([]*ColumnInfo, 0, len(columnInfos))
+			for _, ci := range columnInfos {
+				c := *ci
+				cis = append(cis, &c)
+			}
+
+			cc := &CmdConfig{
+				DBConfig:         DBConfig{Table: "user"},
+				EnableInitialism: true, EnableJSONTag: true,
+				EnableBeegoTag: name == "beego", EnableGormV2Tag: name == "gormv2", EnableXormTag: name == "xorm",
+			}
+			ts := &TableSchema{Name: "user", Columns: cis, Indexes: shuffled}
+			prepareColumnInfos(cc, ts)
+			fields, err := getFields(cc, ts)
+			if err != nil {
+				t.Fatalf("getFields failed, name:%s, err:%v", name, err)
+			}
+			out, err := generateCode(cc, fields)
+			if err != nil {
+				t.Fatalf("generateCode failed, name:%s, err:%v", name, err)
+			}
+
+			if i == 0 {
+				golden = out
+			} else if out != golden {
+				t.Fatalf("generateCode is not deterministic, name:%s, expectation:\n%s\noutput:\n%s", name, golden, out)
+			}
+		}
+
+		goldenFile := filepath.Join("testdata", name+".golden")
+		if *update {
+			if err := os.WriteFile(goldenFile, []byte(golden+"\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		expectation, err := os.ReadFile(goldenFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if golden+"\n" != string(expectation) {
+			t.Errorf("generateCode failed, name:%s, expectation:\n%s\noutput:\n%s", name, expectation, golden)
+		}
+	}
+}
+
+func TestGetMisorderedIndexes(t *testing.T) {
+	ts := &TableSchema{
+		Columns: []*ColumnInfo{{Name: "a", Position: 1}, {Name: "b", Position: 2}, {Name: "c", Position: 3}},
+		Indexes: []*IndexInfo{
+			{Name: "idx_a_b_c", ColumnName: "a", Sequence: 1},
+			{Name: "idx_a_b_c", ColumnName: "b", Sequence: 2},
+			{Name: "idx_a_b_c", ColumnName: "c", Sequence: 3},
+			{Name: "idx_a_c_b", ColumnName: "a", Sequence: 1},
+			{Name: "idx_a_c_b", ColumnName: "c", Sequence: 2},
+			{Name: "idx_a_c_b", ColumnName: "b", Sequence: 3},
+			{Name: "idx_c", ColumnName: "c", Sequence: 1},
+			{Name: "idx_c_b_a", ColumnName: "c", Sequence: 1},
+			{Name: "idx_c_b_a", ColumnName: "b", Sequence: 2},
+			{Name: "idx_c_b_a", ColumnName: "a", Sequence: 3},
+		},
+	}
+
+	expectation := []string{"idx_a_c_b", "idx_c_b_a"}
+	if output := getMisorderedIndexes(ts); !reflect.DeepEqual(output, expectation) {
+		t.Errorf("getMisorderedIndexes failed, expectation:%s, output:%s", expectation, output)
+	}
+}
+
+func TestUniqueTableIndexes(t *testing.T) {
+	a := &TableIndex{Name: "idx_a", Columns: []string{`"A"`}}
+	ab := &TableIndex{Name: "idx_a_b", Columns: []string{`"A"`, `"B"`}}
+	ab2 := &TableIndex{Name: "idx_a_b_2", Columns: []string{`"A"`, `"B"`}}
+	ba := &TableIndex{Name: "idx_b_a", Columns: []string{`"B"`, `"A"`}}
+
 	cases := []struct {
-		input       []string
-		expectation []string
+		input       []*TableIndex
+		expectation []*TableIndex
 	}{
-		{[]string{"a", "a", "b", "b", "c"}, []string{"a", "b", "c"}},
-		{[]string{"a", "b", "a", "c", "b"}, []string{"a", "b", "c"}},
-		{[]string{"a", "b", "c"}, []string{"a", "b", "c"}},
+		{[]*TableIndex{a, ab, ab2}, []*TableIndex{a, ab}},
+		{[]*TableIndex{ab, ba, ab2, a}, []*TableIndex{ab, ba, a}},
+		{[]*TableIndex{a, ab, ba}, []*TableIndex{a, ab, ba}},
 	}
 
 	for _, c := range cases {
-		output := uniqueStrings(c.input)
+		output := uniqueTableIndexes(c.input)
 		if !reflect.DeepEqual(output, c.expectation) {
-			t.Errorf("uniqueStrings failed, expectation:%s, output:%s",
+			t.Errorf("uniqueTableIndexes failed, expectation:%+v, output:%+v",
+				c.expectation, output)
+		}
+	}
+}
+
+func TestGetValidateTag(t *testing.T) {
+	cases := []struct {
+		ci          ColumnInfo
+		vc          ValidateTagConfig
+		expectation string
+	}{
+		{
+			ColumnInfo{
+				Name: "id", Type: "bigint(20) unsigned", DataType: "bigint",
+				IsPrimaryKey: true, IsAutoIncrement: true, IsUnsigned: true,
+			},
+			ValidateTagConfig{},
+			"validate:\"min=0\"",
+		},
+		{
+			ColumnInfo{Name: "name", Type: "varchar(255)", DataType: "varchar", Length: 255},
+			ValidateTagConfig{},
+			"validate:\"required,max=255\"",
+		},
+		{
+			ColumnInfo{Name: "email", Type: "varchar(128)", DataType: "varchar", Length: 128, IsNullable: true},
+			ValidateTagConfig{},
+			"validate:\"omitempty,max=128\"",
+		},
+		{
+			ColumnInfo{Name: "method", Type: "varchar(16)", DataType: "varchar", Length: 16, Default: "POST"},
+			ValidateTagConfig{},
+			"validate:\"max=16\"",
+		},
+		{
+			ColumnInfo{Name: "status", Type: "enum('on','off','not set')", DataType: "enum"},
+			ValidateTagConfig{},
+			"validate:\"required,oneof=on off 'not set'\"",
+		},
+		{
+			ColumnInfo{Name: "age", Type: "tinyint(3) unsigned", DataType: "tinyint", IsUnsigned: true},
+			ValidateTagConfig{},
+			"validate:\"required,min=0,max=255\"",
+		},
+		{
+			ColumnInfo{Name: "age", Type: "tinyint(3) unsigned", DataType: "tinyint", IsUnsigned: true},
+			ValidateTagConfig{DisabledRules: []string{"required", "range"}},
+			"validate:\"min=0\"",
+		},
+		{
+			ColumnInfo{Name: "level", Type: "smallint(6)", DataType: "smallint"},
+			ValidateTagConfig{},
+			"validate:\"required,min=-32768,max=32767\"",
+		},
+		{
+			ColumnInfo{Name: "is_deleted", Type: "tinyint(1)", DataType: "tinyint"},
+			ValidateTagConfig{},
+			"",
+		},
+		{
+			ColumnInfo{Name: "name", Type: "varchar(255)", DataType: "varchar", Length: 255},
+			ValidateTagConfig{SkipColumns: []string{"name"}},
+			"",
+		},
+		{
+			ColumnInfo{Name: "email", Type: "varchar(255)", DataType: "varchar", Length: 255},
+			ValidateTagConfig{ColumnRules: map[string]string{"email": "required,email"}},
+			"validate:\"required,email\"",
+		},
+	}
+
+	for _, c := range cases {
+		output := getValidateTag(&c.ci, &c.vc, "")
+		if output != c.expectation {
+			t.Errorf("getValidateTag failed, expectation:%s, output:%s",
 				c.expectation, output)
 		}
 	}
+
+	nullableCases := []struct {
+		ci          ColumnInfo
+		fieldType   string
+		expectation string
+	}{
+		{
+			ColumnInfo{Name: "email", Type: "varchar(128)", DataType: "varchar", Length: 128, IsNullable: true},
+			GoString,
+			"validate:\"omitempty,max=128\"",
+		},
+		{
+			ColumnInfo{Name: "email", Type: "varchar(128)", DataType: "varchar", Length: 128, IsNullable: true},
+			SQLNullString,
+			"",
+		},
+		{
+			ColumnInfo{Name: "age", Type: "tinyint(3) unsigned", DataType: "tinyint", IsUnsigned: true, IsNullable: true},
+			GureguNullInt,
+			"",
+		},
+		{
+			ColumnInfo{Name: "status", Type: "enum('on','off')", DataType: "enum", IsNullable: true},
+			GureguNullString,
+			"",
+		},
+	}
+
+	for _, c := range nullableCases {
+		output := getValidateTag(&c.ci, &ValidateTagConfig{}, c.fieldType)
+		if output != c.expectation {
+			t.Errorf("getValidateTag failed, fieldType:%s, expectation:%s, output:%s",
+				c.fieldType, c.expectation, output)
+		}
+	}
+}
+
+func TestParseEnumValues(t *testing.T) {
+	cases := []struct {
+		input       string
+		expectation []string
+	}{
+		{"enum('a','b','c')", []string{"a", "b", "c"}},
+		{"set('read','write')", []string{"read", "write"}},
+		{"enum('it''s','a,b')", []string{"it's", "a,b"}},
+		{"varchar(255)", nil},
+	}
+
+	for _, c := range cases {
+		output := parseEnumValues(c.input)
+		if !reflect.DeepEqual(output, c.expectation) {
+			t.Errorf("parseEnumValues failed, input:%s, expectation:%v, output:%v",
+				c.input, c.expectation, output)
+		}
+	}
+}
+
+func TestConvertCase(t *testing.T) {
+	cases := []struct {
+		input       string
+		naming      string
+		expectation string
+	}{
+		{"user_name", NamingRaw, "user_name"},
+		{"user_name", "", "user_name"},
+		{"USER_NAME", NamingSnake, "user_name"},
+		{"HTTPServer", NamingSnake, "http_server"},
+		{"user_name", NamingCamel, "userName"},
+		{"user_id", NamingCamel, "userId"},
+		{"user name", NamingPascal, "UserName"},
+		{"order-id", NamingPascal, "OrderId"},
+		{"userName", NamingKebab, "user-name"},
+		{"2fa_enabled", NamingCamel, "2faEnabled"},
+	}
+
+	for _, c := range cases {
+		output := convertCase(c.input, c.naming)
+		if output != c.expectation {
+			t.Errorf("convertCase failed, input:%s, naming:%s, expectation:%s, output:%s",
+				c.input, c.naming, c.expectation, output)
+		}
+	}
+}
+
+func TestGetSerializationTags(t *testing.T) {
+	ci := ColumnInfo{Name: "user_name", IsNullable: true}
+	tc := TagConfig{Naming: NamingCamel, OmitEmpty: true}
+
+	cases := []struct {
+		output      string
+		expectation string
+	}{
+		{getYAMLTag(&ci, &tc), "yaml:\"userName,omitempty\""},
+		{getTOMLTag(&ci, &tc), "toml:\"userName,omitempty\""},
+		{getBSONTag(&ci, &tc, true), "bson:\"userName,omitempty\""},
+		{getMsgpackTag(&ci, &tc), "msgpack:\"userName,omitempty\""},
+		{getMapstructureTag(&ci, &tc), "mapstructure:\"userName,omitempty\""},
+		{getFormTag(&ci, &tc), "form:\"userName,omitempty\""},
+		{getBSONTag(&ColumnInfo{Name: "id", IsPrimaryKey: true}, &TagConfig{}, true), "bson:\"_id,omitempty\""},
+		{getBSONTag(&ColumnInfo{Name: "id", IsPrimaryKey: true}, &TagConfig{}, false), "bson:\"id\""},
+	}
+
+	for _, c := range cases {
+		if c.output != c.expectation {
+			t.Errorf("get serialization tag failed, expectation:%s, output:%s",
+				c.expectation, c.output)
+		}
+	}
+}
+
+func TestGenerateCustomTag(t *testing.T) {
+	cts, err := parseCustomTags([]CustomTagConfig{
+		{Key: "db", Value: "{{ .Name }}"},
+		{Key: "graphql", Value: "{{ .Name | camel }}{{ if .IsNullable }},optional{{ end }}"},
+		{Key: "empty", Value: "{{ if .IsPrimaryKey }}pk{{ end }}"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ci := ColumnInfo{Name: "user_name", IsNullable: true}
+	expectations := []string{"db:\"user_name\"", "graphql:\"userName,optional\"", ""}
+	for i, ct := range cts {
+		output := generateCustomTag(&ci, ct)
+		if output != expectations[i] {
+			t.Errorf("generateCustomTag failed, expectation:%s, output:%s",
+				expectations[i], output)
+		}
+	}
+
+	if _, err = parseCustomTags([]CustomTagConfig{{Key: "bad", Value: "{{ .Name "}}); err == nil {
+		t.Error("parseCustomTags should fail with invalid template")
+	}
+	for _, key := range []string{"my tag", "db:name", `db"`, "db`", "db\t"} {
+		if _, err = parseCustomTags([]CustomTagConfig{{Key: key, Value: "{{ .Name }}"}}); err == nil {
+			t.Errorf("parseCustomTags should fail with invalid key:%q", key)
+		}
+	}
+}
+
+func TestSingularize(t *testing.T) {
+	cases := []struct {
+		input       string
+		exceptions  map[string]string
+		expectation string
+	}{
+		{"users", nil, "user"},
+		{"categories", nil, "category"},
+		{"people", nil, "person"},
+		{"addresses", nil, "address"},
+		{"boxes", nil, "box"},
+		{"statuses", nil, "status"},
+		{"status", nil, "status"},
+		{"matrices", nil, "matrix"},
+		{"analyses", nil, "analysis"},
+		{"hypotheses", nil, "hypothesis"},
+		{"bases", nil, "basis"},
+		{"databases", nil, "database"},
+		{"axes", nil, "axis"},
+		{"crises", nil, "crisis"},
+		{"taxes", nil, "tax"},
+		{"syntaxes", nil, "syntax"},
+		{"knives", nil, "knife"},
+		{"wolves", nil, "wolf"},
+		{"movies", nil, "movie"},
+		{"news", nil, "news"},
+		{"user", nil, "user"},
+		{"ACCOUNTS", nil, "ACCOUNT"},
+		{"sms", map[string]string{"sms": "sms"}, "sms"},
+	}
+
+	for _, c := range cases {
+		output := singularize(c.input, c.exceptions)
+		if output != c.expectation {
+			t.Errorf("singularize failed, input:%s, expectation:%s, output:%s",
+				c.input, c.expectation, output)
+		}
+	}
+}
+
+func TestGetStructTableName(t *testing.T) {
+	cases := []struct {
+		cc          CmdConfig
+		expectation string
+	}{
+		{CmdConfig{DBConfig: DBConfig{Table: "t_user_accounts"}}, "t_user_accounts"},
+		{CmdConfig{DBConfig: DBConfig{Table: "t_user_accounts"}, TablePrefixes: []string{"tbl_", "t_"}}, "user_accounts"},
+		{CmdConfig{DBConfig: DBConfig{Table: "user_tab"}, TableSuffixes: []string{"_tab"}}, "user"},
+		{CmdConfig{DBConfig: DBConfig{Table: "t_"}, TablePrefixes: []string{"t_"}}, "t_"},
+		{
+			CmdConfig{DBConfig: DBConfig{Table: "t_user_accounts"}, TablePrefixes: []string{"t_"}, EnableSingularTable: true},
+			"user_account",
+		},
+		{CmdConfig{DBConfig: DBConfig{Table: "categories"}, EnableSingularTable: true}, "category"},
+		{
+			CmdConfig{
+				DBConfig: DBConfig{Table: "tbl_user_data"}, TablePrefixes: []string{"tbl_"},
+				EnableSingularTable: true, SingularExceptions: map[string]string{"user_data": "user_profile"},
+			},
+			"user_profile",
+		},
+	}
+
+	for _, c := range cases {
+		output := getStructTableName(&c.cc)
+		if output != c.expectation {
+			t.Errorf("getStructTableName failed, table:%s, expectation:%s, output:%s",
+				c.cc.Table, c.expectation, output)
+		}
+	}
+}
+
+func TestLoadCmdConfig(t *testing.T) {
+	os.Setenv("GROM_TEST_DB_PASSWORD", "p@ss/word")
+	os.Setenv("GROM_TEST_DB_PORT", "3307")
+	os.Setenv("GROM_TEST_NAMING", "camel")
+	defer os.Unsetenv("GROM_TEST_DB_PASSWORD")
+	defer os.Unsetenv("GROM_TEST_DB_PORT")
+	defer os.Unsetenv("GROM_TEST_NAMING")
+
+	files := map[string]string{
+		"grom.json": `{
+    "host": "localhost",
+    "port": 3306,
+    "password": "${GROM_TEST_DB_PASSWORD}",
+    "enable_json_tag": true,
+    "json_tag": {"naming": "${GROM_TEST_NAMING}"},
+    "profiles": {
+        "dev": {"host": "dev.local", "port": "${GROM_TEST_DB_PORT}", "json_tag": {"omit_empty": true}}
+    }
+}`,
+		"grom.yaml": `host: localhost
+port: 3306
+password: ${GROM_TEST_DB_PASSWORD}
+enable_json_tag: true
+json_tag:
+  naming: ${GROM_TEST_NAMING}
+profiles:
+  dev:
+    host: dev.local
+    port: ${GROM_TEST_DB_PORT}
+    json_tag:
+      omit_empty: true
+`,
+		"grom.toml": `host = "localhost"
+port = 3306
+password = "${GROM_TEST_DB_PASSWORD}"
+enable_json_tag = true
+
+[json_tag]
+naming = "${GROM_TEST_NAMING}"
+
+[profiles.dev]
+host = "dev.local"
+port = "${GROM_TEST_DB_PORT}"
+
+[profiles.dev.json_tag]
+omit_empty = true
+`,
+	}
+
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		cc, err := LoadCmdConfig(path, "")
+		if err != nil {
+			t.Fatalf("LoadCmdConfig failed, file:%s, err:%v", name, err)
+		}
+		if cc.Host != "localhost" || cc.Port != 3306 || cc.Password != "p@ss/word" ||
+			!cc.EnableJSONTag || cc.JSONTag.Naming != NamingCamel || cc.JSONTag.OmitEmpty {
+			t.Errorf("LoadCmdConfig failed, file:%s, output:%+v", name, cc)
+		}
+
+		cc, err = LoadCmdConfig(path, "dev")
+		if err != nil {
+			t.Fatalf("LoadCmdConfig failed, file:%s, profile:dev, err:%v", name, err)
+		}
+		if cc.Host != "dev.local" || cc.Port != 3307 || cc.Password != "p@ss/word" ||
+			cc.JSONTag.Naming != NamingCamel || !cc.JSONTag.OmitEmpty {
+			t.Errorf("LoadCmdConfig failed, file:%s, profile:dev, output:%+v", name, cc)
+		}
+
+		if _, err = LoadCmdConfig(path, "prod"); err == nil {
+			t.Errorf("LoadCmdConfig should fail with unknown profile, file:%s", name)
+		}
+	}
+}
+
+func TestApplyEnvConfig(t *testing.T) {
+	os.Setenv("GROM_HOST", "env.local")
+	os.Setenv("GROM_PORT", "3308")
+	os.Setenv("GROM_ENABLE_GORM_V2_TAG", "true")
+	os.Setenv("GROM_TABLE_PREFIXES", "t_, tbl_")
+	defer os.Unsetenv("GROM_HOST")
+	defer os.Unsetenv("GROM_PORT")
+	defer os.Unsetenv("GROM_ENABLE_GORM_V2_TAG")
+	defer os.Unsetenv("GROM_TABLE_PREFIXES")
+
+	cc := CmdConfig{DBConfig: DBConfig{Host: "localhost", Port: 3306, User: "user"}}
+	if err := ApplyEnvConfig(&cc); err != nil {
+		t.Fatal(err)
+	}
+	if cc.Host != "env.local" || cc.Port != 3308 || cc.User != "user" || !cc.EnableGormV2Tag ||
+		!reflect.DeepEqual(cc.TablePrefixes, []string{"t_", "tbl_"}) {
+		t.Errorf("ApplyEnvConfig failed, output:%+v", cc)
+	}
+
+	os.Setenv("GROM_PORT", "port")
+	if err := ApplyEnvConfig(&cc); err == nil {
+		t.Error("ApplyEnvConfig should fail with invalid port")
+	}
+}
+
+func TestMarshalCmdConfig(t *testing.T) {
+	cc := CmdConfig{
+		DBConfig:      DBConfig{Host: "localhost", Port: 3306},
+		EnableJSONTag: true,
+		JSONTag:       TagConfig{Naming: NamingCamel},
+	}
+
+	dir := t.TempDir()
+	for _, format := range []string{ConfigFormatJSON, ConfigFormatYAML, ConfigFormatTOML} {
+		b, err := MarshalCmdConfig(&cc, format)
+		if err != nil {
+			t.Fatalf("MarshalCmdConfig failed, format:%s, err:%v", format, err)
+		}
+
+		path := filepath.Join(dir, "grom."+format)
+		if err = os.WriteFile(path, b, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		output, err := LoadCmdConfig(path, "")
+		if err != nil {
+			t.Fatalf("LoadCmdConfig failed, format:%s, err:%v", format, err)
+		}
+		if !reflect.DeepEqual(*output, cc) {
+			t.Errorf("MarshalCmdConfig failed, format:%s, expectation:%+v, output:%+v", format, cc, *output)
+		}
+	}
+
+	if _, err := MarshalCmdConfig(&cc, "ini"); err == nil {
+		t.Error("MarshalCmdConfig should fail with invalid format")
+	}
+}
+
+func TestExpandEnv(t *testing.T) {
+	os.Setenv("GROM_TEST_VALUE", "value")
+	os.Setenv("GROM_TEST_EMPTY", "")
+	defer os.Unsetenv("GROM_TEST_VALUE")
+	defer os.Unsetenv("GROM_TEST_EMPTY")
+
+	cases := []struct {
+		input       string
+		expectation string
+	}{
+		{"${GROM_TEST_VALUE}", "value"},
+		{"a-${GROM_TEST_VALUE}-b", "a-value-b"},
+		{"${GROM_TEST_UNSET}", ""},
+		{"${GROM_TEST_UNSET:-default}", "default"},
+		{"${GROM_TEST_EMPTY:-default}", "default"},
+		{"$GROM_TEST_VALUE", "$GROM_TEST_VALUE"},
+		{"pa$$word", "pa$$word"},
+	}
+
+	for _, c := range cases {
+		output := expandEnv(c.input)
+		if output != c.expectation {
+			t.Errorf("expandEnv failed, input:%s, expectation:%s, output:%s",
+				c.input, c.expectation, output)
+		}
+	}
+}
+
+func TestGetMySQLConfig(t *testing.T) {
+	cases := []struct {
+		dc          DBConfig
+		expectation string
+		database    string
+	}{
+		{
+			DBConfig{Host: "localhost", Port: 3306, User: "user", Password: "password", Database: "database"},
+			"user:password@tcp(localhost:3306)/database?loc=Local&parseTime=true&charset=utf8",
+			"database",
+		},
+		{
+			DBConfig{Host: "localhost", User: "user", Password: "p@ss/w:rd", Database: "database", Charset: "utf8mb4"},
+			"user:p@ss/w:rd@tcp(localhost:3306)/database?loc=Local&parseTime=true&charset=utf8mb4",
+			"database",
+		},
+		{
+			DBConfig{Socket: "/var/run/mysqld/mysqld.sock", User: "user", Database: "database", TLS: "false"},
+			"user@unix(/var/run/mysqld/mysqld.sock)/database?loc=Local&parseTime=true&tls=false&charset=utf8",
+			"database",
+		},
+		{
+			DBConfig{Host: "::1", Port: 3307, User: "user", Database: "database", Params: map[string]string{"timeout": "5s", "loc": "Asia/Shanghai"}},
+			"user@tcp([::1]:3307)/database?loc=Asia%2FShanghai&parseTime=true&timeout=5s&charset=utf8",
+			"database",
+		},
+		{
+			DBConfig{DSN: "user:password@tcp(db.local:3306)/dsn_database?tls=skip-verify", Host: "ignored"},
+			"user:password@tcp(db.local:3306)/dsn_database?tls=skip-verify",
+			"dsn_database",
+		},
+		{
+			DBConfig{DSN: "user:password@tcp(db.local:3306)/dsn_database", Database: "database", TLS: "preferred", Charset: "utf8mb4"},
+			"user:password@tcp(db.local:3306)/database?tls=preferred&charset=utf8mb4",
+			"database",
+		},
+		{
+			DBConfig{DSN: "user@tcp(db.local:3306)/dsn_database", User: "ignored", Password: "password"},
+			"user:password@tcp(db.local:3306)/dsn_database",
+			"dsn_database",
+		},
+		{
+			DBConfig{DSN: "tcp(db.local:3306)/dsn_database", User: "user", Password: "password"},
+			"user:password@tcp(db.local:3306)/dsn_database",
+			"dsn_database",
+		},
+	}
+
+	for _, c := range cases {
+		cfg, err := getMySQLConfig(&c.dc)
+		if err != nil {
+			t.Errorf("getMySQLConfig failed, config:%+v, err:%v", c.dc, err)
+			continue
+		}
+		if output := cfg.FormatDSN(); output != c.expectation {
+			t.Errorf("getMySQLConfig failed, expectation:%s, output:%s", c.expectation, output)
+		}
+		if c.dc.Database != c.database {
+			t.Errorf("getMySQLConfig failed, expectation database:%s, output database:%s", c.database, c.dc.Database)
+		}
+	}
+
+	for _, dc := range []DBConfig{
+		{DSN: "invalid dsn"},
+		{Host: "localhost", TLS: "unknown"},
+		{Host: "localhost", TLS: "false", TLSCA: "ca.pem"},
+		{Host: "localhost", TLSCert: "cert.pem"},
+	} {
+		if _, err := getMySQLConfig(&dc); err == nil {
+			t.Errorf("getMySQLConfig should fail, config:%+v", dc)
+		}
+	}
+}
+
+func TestParseMyCnf(t *testing.T) {
+	content := `# mysql option file
+[mysql]
+user = ignored
+
+[client]
+host = db.local   # the host
+port=3307
+user = "quoted user"
+password = 'p#ss word'
+default-character-set = utf8mb4
+skip-ssl
+; comment
+[mysqldump]
+user = ignored
+`
+	expectation := map[string]string{
+		"host":                  "db.local",
+		"port":                  "3307",
+		"user":                  "quoted user",
+		"password":              "p#ss word",
+		"default_character_set": "utf8mb4",
+		"skip_ssl":              "",
+	}
+
+	options, err := parseMyCnf(strings.NewReader(content), "client")
+	if err != nil {
+		t.Fatalf("parseMyCnf failed, err:%v", err)
+	}
+	if !reflect.DeepEqual(options, expectation) {
+		t.Errorf("parseMyCnf failed, expectation:%v, output:%v", expectation, options)
+	}
+}
+
+func TestApplyCredentials(t *testing.T) {
+	dir := t.TempDir()
+	defaultsFile := filepath.Join(dir, "my.cnf")
+	passwordFile := filepath.Join(dir, "password")
+	if err := os.WriteFile(defaultsFile, []byte("[client]\nhost=db.local\nport=3307\nuser=cnf_user\npassword=cnf_password\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(passwordFile, []byte("file_password\nignored\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		dc          DBConfig
+		stdin       string
+		expectation DBConfig
+	}{
+		{
+			DBConfig{DefaultsFile: defaultsFile, User: "user"},
+			"",
+			DBConfig{DefaultsFile: defaultsFile, Host: "db.local", Port: 3307, User: "user", Password: "cnf_password"},
+		},
+		{
+			DBConfig{DefaultsFile: defaultsFile, PasswordFile: passwordFile},
+			"",
+			DBConfig{DefaultsFile: defaultsFile, PasswordFile: passwordFile, Host: "db.local", Port: 3307, User: "cnf_user", Password: "file_password"},
+		},
+		{
+			DBConfig{DefaultsFile: defaultsFile, PasswordFile: "-", Host: "localhost", Port: 3306},
+			"stdin_password\r\n",
+			DBConfig{DefaultsFile: defaultsFile, PasswordFile: "-", Host: "localhost", Port: 3306, User: "cnf_user", Password: "stdin_password"},
+		},
+		{
+			DBConfig{DSN: "user@tcp(localhost:3306)/database", DefaultsFile: defaultsFile, PasswordFile: "-"},
+			"stdin_password",
+			DBConfig{DSN: "user@tcp(localhost:3306)/database", DefaultsFile: defaultsFile, PasswordFile: "-", User: "cnf_user", Password: "stdin_password"},
+		},
+		{
+			DBConfig{DSN: "tcp(localhost:3306)/database", DefaultsFile: defaultsFile},
+			"",
+			DBConfig{DSN: "tcp(localhost:3306)/database", DefaultsFile: defaultsFile, User: "cnf_user", Password: "cnf_password"},
+		},
+	}
+
+	for _, c := range cases {
+		dc := c.dc
+		if err := ApplyCredentials(&dc, strings.NewReader(c.stdin)); err != nil {
+			t.Errorf("ApplyCredentials failed, config:%+v, err:%v", c.dc, err)
+			continue
+		}
+		if !reflect.DeepEqual(dc, c.expectation) {
+			t.Errorf("ApplyCredentials failed, expectation:%+v, output:%+v", c.expectation, dc)
+		}
+	}
+
+	dc := DBConfig{DefaultsFile: filepath.Join(dir, "not_exist.cnf")}
+	if err := ApplyCredentials(&dc, strings.NewReader("")); err == nil {
+		t.Errorf("ApplyCredentials should fail, config:%+v", dc)
+	}
+}
+
+func TestDecryptMyLogin(t *testing.T) {
+	key := []byte("0123456789abcdefghij")
+	aesKey := make([]byte, 16)
+	for i, b := range key {
+		aesKey[i%16] ^= b
+	}
+	block, err := aes.NewCipher(aesKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content := append([]byte{0, 0, 0, 0}, key...)
+	for _, line := range []string{"[local]\n", "user = \"login_user\"\n", "password = \"login_password\"\n", "host = \"db.local\"\n"} {
+		padding := aes.BlockSize - len(line)%aes.BlockSize
+		plain := append([]byte(line), bytes.Repeat([]byte{byte(padding)}, padding)...)
+		encrypted := make([]byte, len(plain))
+		for i := 0; i < len(plain); i += aes.BlockSize {
+			block.Encrypt(encrypted[i:i+aes.BlockSize], plain[i:i+aes.BlockSize])
+		}
+		size := make([]byte, 4)
+		binary.LittleEndian.PutUint32(size, uint32(len(encrypted)))
+		content = append(append(content, size...), encrypted...)
+	}
+
+	plain, err := decryptMyLogin(content)
+	if err != nil {
+		t.Fatalf("decryptMyLogin failed, err:%v", err)
+	}
+	options, err := parseMyCnf(bytes.NewReader(plain), "local")
+	if err != nil {
+		t.Fatalf("parseMyCnf failed, err:%v", err)
+	}
+	expectation := map[string]string{"user": "login_user", "password": "login_password", "host": "db.local"}
+	if !reflect.DeepEqual(options, expectation) {
+		t.Errorf("decryptMyLogin failed, expectation:%v, output:%v", expectation, options)
+	}
+
+	if _, err = decryptMyLogin(content[:10]); err == nil {
+		t.Error("decryptMyLogin should fail with truncated content")
+	}
+}
+
+func TestValidateCmdConfig(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "grom.yaml")
+	content := `host: localhost
+database: database
+table: table
+enable_jsn_tag: true
+json_tag:
+  namng: snake
+custom_tags:
+  - key: db
+    valu: "{{ .Name }}"
+profiles:
+  dev:
+    hots: dev.local
+`
+	if err := os.WriteFile(filePath, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cc, err := LoadCmdConfig(filePath, "")
+	if err != nil {
+		t.Fatalf("LoadCmdConfig failed, err:%v", err)
+	}
+	expectation := []string{
+		"unknown key: custom_tags[0].valu",
+		"unknown key: enable_jsn_tag",
+		"unknown key: json_tag.namng",
+		"unknown key: profiles.dev.hots",
+	}
+	if problems := getConfigProblems(cc); !reflect.DeepEqual(problems, expectation) {
+		t.Errorf("getConfigProblems failed, expectation:%q, output:%q", expectation, problems)
+	}
+
+	cases := []struct {
+		cc          CmdConfig
+		expectation []string
+	}{
+		{
+			CmdConfig{DBConfig: DBConfig{Host: "localhost", Database: "database", Table: "table"}, PackageName: "model"},
+			nil,
+		},
+		{
+			CmdConfig{DBConfig: DBConfig{DSN: "user@tcp(localhost:3306)/database", Table: "table"}},
+			nil,
+		},
+		{
+			CmdConfig{
+				DBConfig:         DBConfig{Host: "localhost", TLS: "unknown"},
+				PackageName:      "my-model",
+				StructName:       "1User",
+				EnableGormTag:    true,
+				EnableGormV2Tag:  true,
+				EnableSQLNull:    true,
+				EnableGureguNull: true,
+				XMLTag:           TagConfig{Naming: "upper"},
+				Views:            "all",
+				ValidateTag:      ValidateTagConfig{DisabledRules: []string{"min"}},
+				Conventions: ConventionConfig{
+					DisabledRules: []string{"version", "created"},
+					Columns:       map[string][]string{"deleted_at": {"removed_at"}, "updated": {"modified_at"}},
+				},
+				BaseModel:      BaseModelConfig{Name: "model.base"},
+				IncludeColumns: []string{"user.id", "user"},
+				ExcludeColumns: []string{".name"},
+				RenameColumns:  map[string]string{"user.usr_nm": "userName", "user.": "Name"},
+				CustomTags:     []CustomTagConfig{{Key: "db", Value: "{{ .Name"}},
+			},
+			[]string{
+				"GORM_TAG (enable_gorm_tag) and GORM_V2_TAG (enable_gorm_v2_tag) are mutually exclusive",
+				"GUREGU_NULL (enable_guregu_null) and SQL_NULL (enable_sql_null) are mutually exclusive",
+				"package name is not a valid go identifier: my-model",
+				"struct name is not a valid go identifier: 1User",
+				"invalid connection: invalid tls mode: unknown",
+				"table is required",
+				"xml_tag.naming must in [raw,snake,camel,pascal,kebab]: upper",
+				"views must in [include,exclude,only]: all",
+				"validate_tag.disabled_rules must in [required,length,unsigned,enum,range,check]: min",
+				"conventions rules must in [created_at,updated_at,deleted_at,version,is_deleted]: created",
+				"conventions rules must in [created_at,updated_at,deleted_at,version,is_deleted]: updated",
+				"base_model.name is not a valid exported go type: model.base",
+				"base_model.columns is required for model.base",
+				"base_model.import is required for model.base",
+				"include_columns must be in the form of table.column: user",
+				"exclude_columns must be in the form of table.column: .name",
+				"rename_columns must be in the form of table.column: user.",
+				"rename_columns.user.usr_nm is not a valid exported go identifier: userName",
+				"invalid custom tags: parse custom tag db err: template: db:1: unclosed action",
+			},
+		},
+		{
+			CmdConfig{DBConfig: DBConfig{Host: "localhost", Table: "table"}},
+			[]string{"database is required"},
+		},
+		{
+			CmdConfig{
+				DBConfig:   DBConfig{Host: "localhost", Database: "database", Table: "table"},
+				CustomTags: []CustomTagConfig{{Key: "my tag", Value: "{{ .Name }}"}},
+			},
+			[]string{"invalid custom tags: custom tag key contains space, quote, colon or control characters: my tag"},
+		},
+	}
+
+	for _, c := range cases {
+		if problems := getConfigProblems(&c.cc); !reflect.DeepEqual(problems, c.expectation) {
+			t.Errorf("getConfigProblems failed, expectation:%q, output:%q", c.expectation, problems)
+		}
+	}
+
+	if output := (&ConfigError{Problems: []string{"a", "b"}}).Error(); output != "invalid config:\n  - a\n  - b" {
+		t.Errorf("ConfigError.Error failed, output:%q", output)
+	}
+}
+
+func TestMarshalValue(t *testing.T) {
+	schema := &TableSchema{
+		Name:    "user",
+		Comment: "user table",
+		Columns: []*ColumnInfo{{Name: "id", DataType: "bigint", Type: "bigint unsigned", Precision: 20, Position: 1, IsPrimaryKey: true, IsAutoIncrement: true, IsUnsigned: true}},
+		Indexes: []*IndexInfo{{Name: "uniq_name", ColumnName: "name", Sequence: 1, IsUnique: true}},
+	}
+
+	cases := []struct {
+		format      string
+		expectation string
+	}{
+		{ConfigFormatJSON, `{
+    "name": "user",
+    "comment": "user table",
+    "columns": [
+        {
+            "name": "id",
+            "data_type": "bigint",
+            "type": "bigint unsigned",
+            "default": "",
+            "comment": "",
+            "length": 0,
+            "precision": 20,
+            "scale": 0,
+            "position": 1,
+            "is_primary_key": true,
+            "is_auto_increment": true,
+            "is_unsigned": true,
+            "is_nullable": false
+        }
+    ],
+    "indexes": [
+        {
+            "name": "uniq_name",
+            "column_name": "name",
+            "comment": "",
+            "sequence": 1,
+            "is_unique": true
+        }
+    ]
+}`},
+		{ConfigFormatYAML, `name: user
+comment: user table
+columns:
+  - name: id
+    data_type: bigint
+    type: bigint unsigned
+    default: ""
+    comment: ""
+    length: 0
+    precision: 20
+    scale: 0
+    position: 1
+    is_primary_key: true
+    is_auto_increment: true
+    is_unsigned: true
+    is_nullable: false
+indexes:
+  - name: uniq_name
+    column_name: name
+    comment: ""
+    sequence: 1
+    is_unique: true
+`},
+	}
+
+	for _, c := range cases {
+		b, err := MarshalValue(schema, c.format)
+		if err != nil {
+			t.Errorf("MarshalValue failed, format:%s, err:%v", c.format, err)
+			continue
+		}
+		if output := string(b); output != c.expectation {
+			t.Errorf("MarshalValue failed, format:%s, expectation:%s, output:%s", c.format, c.expectation, output)
+		}
+	}
+
+	if _, err := MarshalValue(schema, "xml"); err == nil {
+		t.Error("MarshalValue should fail with invalid format")
+	}
+}
+
+func TestGetSchema(t *testing.T) {
+	dir := t.TempDir()
+	schemaFile := filepath.Join(dir, "schema.json")
+	content := `{
+    "version": 1,
+    "table": "user_info",
+    "comment": "user info",
+    "columns": [
+        {"name": "id", "data_type": "bigint", "type": "bigint unsigned", "position": 1, "is_primary_key": true, "is_auto_increment": true, "is_unsigned": true},
+        {"name": "user_name", "data_type": "varchar", "type": "varchar(32)", "length": 32, "position": 2, "comment": "name"}
+    ],
+    "indexes": [
+        {"name": "uniq_user_name", "column_name": "user_name", "sequence": 1, "is_unique": true}
+    ]
+}`
+	if err := os.WriteFile(schemaFile, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cc := &CmdConfig{SchemaFile: schemaFile, EnableInitialism: true, EnableJSONTag: true, EnableGormV2Tag: true}
+	schema, err := GetSchema(cc)
+	if err != nil {
+		t.Fatalf("GetSchema failed, err:%v", err)
+	}
+	expectation := []*SchemaField{
+		{Name: "ID", Type: "uint64", Tag: `json:"id" gorm:"primaryKey;autoIncrement;column:id"`, Column: "id"},
+		{Name: "UserName", Type: "string", Tag: `json:"user_name" gorm:"column:user_name;type:varchar(32);not null;uniqueIndex:uniq_user_name;comment:name"`, Column: "user_name"},
+	}
+	if schema.Version != SchemaVersion || schema.Table != "user_info" || schema.StructName != "UserInfo" || schema.Comment != "user info" {
+		t.Errorf("GetSchema failed, output:%+v", schema)
+	}
+	if !reflect.DeepEqual(schema.Fields, expectation) {
+		b, _ := json.Marshal(schema.Fields)
+		t.Errorf("GetSchema failed, output fields:%s", b)
+	}
+
+	// the output schema can be fed back as the schema source
+	b, err := MarshalValue(schema, ConfigFormatJSON)
+	if err != nil {
+		t.Fatalf("MarshalValue failed, err:%v", err)
+	}
+	outputFile := filepath.Join(dir, "output.json")
+	if err = os.WriteFile(outputFile, b, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cc = &CmdConfig{DBConfig: DBConfig{Table: "user_info"}, SchemaFile: outputFile, EnableInitialism: true, EnableJSONTag: true, EnableGormV2Tag: true}
+	output, err := GetSchema(cc)
+	if err != nil {
+		t.Fatalf("GetSchema failed, err:%v", err)
+	}
+	if !reflect.DeepEqual(output, schema) {
+		t.Errorf("GetSchema failed, expectation:%+v, output:%+v", schema, output)
+	}
+
+	for _, c := range []struct {
+		content string
+		table   string
+	}{
+		{`{"version": 2, "table": "t", "columns": [{"name": "id"}]}`, ""},
+		{`{"table": "t", "columns": [{"name": "id"}]}`, ""},
+		{`{"version": 1, "table": "t", "columns": []}`, ""},
+		{`{"version": 1, "table": "t", "columns": [{"name": "id"}]}`, "other"},
+	} {
+		if err = os.WriteFile(schemaFile, []byte(c.content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = GetSchema(&CmdConfig{SchemaFile: schemaFile, DBConfig: DBConfig{Table: c.table}}); err == nil {
+			t.Errorf("GetSchema should fail, content:%s, table:%s", c.content, c.table)
+		}
+	}
+}
+
+func TestLoadSnapshot(t *testing.T) {
+	snapshot := &Snapshot{
+		Version:  SchemaVersion,
+		Database: "database",
+		Tables: []*TableSchema{
+			{
+				Name:    "order",
+				Comment: "order table",
+				Columns: []*ColumnInfo{
+					{Name: "id", DataType: "int", Type: "int", Position: 1, IsPrimaryKey: true},
+					{Name: "user_id", DataType: "int", Type: "int", Position: 2},
+				},
+				Indexes:     []*IndexInfo{{Name: "idx_user_id", ColumnName: "user_id", Sequence: 1}},
+				ForeignKeys: []*ForeignKeyInfo{{Name: "fk_user", ColumnName: "user_id", ReferencedTable: "user", ReferencedColumn: "id", Sequence: 1}},
+			},
+			{
+				Name:    "user",
+				Columns: []*ColumnInfo{{Name: "id", DataType: "int", Type: "int", Position: 1, IsPrimaryKey: true}},
+				Indexes: []*IndexInfo{},
+			},
+		},
+	}
+
+	dir := t.TempDir()
+	for _, format := range []string{ConfigFormatJSON, ConfigFormatYAML} {
+		b, err := MarshalValue(snapshot, format)
+		if err != nil {
+			t.Fatalf("MarshalValue failed, err:%v", err)
+		}
+		snapshotFile := filepath.Join(dir, "grom.snapshot."+format)
+		if err = os.WriteFile(snapshotFile, b, 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		output, err := LoadSnapshot(snapshotFile)
+		if err != nil {
+			t.Fatalf("LoadSnapshot failed, format:%s, err:%v", format, err)
+		}
+		if !reflect.DeepEqual(output, snapshot) {
+			t.Errorf("LoadSnapshot failed, format:%s, expectation:%+v, output:%+v", format, snapshot, output)
+		}
+
+		cc := &CmdConfig{DBConfig: DBConfig{Table: "order"}, SnapshotFile: snapshotFile, EnableJSONTag: true}
+		if problems := getConfigProblems(cc); len(problems) != 0 {
+			t.Errorf("getConfigProblems failed, output:%q", problems)
+		}
+		schema, err := GetSchema(cc)
+		if err != nil {
+			t.Fatalf("GetSchema failed, format:%s, err:%v", format, err)
+		}
+		if schema.StructName != "Order" || len(schema.Fields) != 2 || schema.Fields[1].Tag != `json:"user_id"` {
+			t.Errorf("GetSchema failed, format:%s, output:%+v", format, schema)
+		}
+
+		cc = &CmdConfig{DBConfig: DBConfig{Table: "product"}, SnapshotFile: snapshotFile}
+		expectation := []string{"table is not found in snapshot file: product"}
+		if problems := getConfigProblems(cc); !reflect.DeepEqual(problems, expectation) {
+			t.Errorf("getConfigProblems failed, expectation:%q, output:%q", expectation, problems)
+		}
+	}
+}
+
+func TestMatchTables(t *testing.T) {
+	tables := []string{"order", "order_bak", "user", "user_role", "user_role_bak"}
+
+	cases := []struct {
+		includes    []string
+		excludes    []string
+		expectation []string
+	}{
+		{nil, nil, tables},
+		{[]string{"user*"}, nil, []string{"user", "user_role", "user_role_bak"}},
+		{[]string{"user_*", "order"}, []string{"*_bak"}, []string{"order", "user_role"}},
+		{nil, []string{"*_bak"}, []string{"order", "user", "user_role"}},
+		{[]string{"product"}, nil, []string{}},
+	}
+
+	for _, c := range cases {
+		output, err := matchTables(tables, c.includes, c.excludes)
+		if err != nil {
+			t.Errorf("matchTables failed, err:%v", err)
+			continue
+		}
+		if !reflect.DeepEqual(output, c.expectation) {
+			t.Errorf("matchTables failed, expectation:%v, output:%v", c.expectation, output)
+		}
+	}
+
+	if _, err := matchTables(tables, []string{"[user"}, nil); err == nil {
+		t.Error("matchTables should fail with invalid pattern")
+	}
+}
+
+func TestConvertTables(t *testing.T) {
+	snapshot := &Snapshot{Version: SchemaVersion, Database: "database"}
+	tables := make([]string, 0, 20)
+	for i := 0; i < 20; i++ {
+		table := "table_" + strconv.Itoa(i)
+		tables = append(tables, table)
+		snapshot.Tables = append(snapshot.Tables, &TableSchema{
+			Name: table,
+			Columns: []*ColumnInfo{
+				{Name: "id", DataType: "int", Type: "int", Position: 1, IsPrimaryKey: true},
+				{Name: "name_" + strconv.Itoa(i), DataType: "varchar", Type: "varchar(32)", Length: 32, Position: 2},
+			},
+			Indexes: []*IndexInfo{},
+		})
+	}
+
+	dir := t.TempDir()
+	b, err := MarshalValue(snapshot, ConfigFormatJSON)
+	if err != nil {
+		t.Fatalf("MarshalValue failed, err:%v", err)
+	}
+	snapshotFile := filepath.Join(dir, "grom.snapshot.json")
+	if err = os.WriteFile(snapshotFile, b, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cc := CmdConfig{SnapshotFile: snapshotFile, EnableJSONTag: true, EnableGormV2Tag: true}
+	var expectation []*TableOutput
+	for _, table := range tables {
+		tc := cc
+		tc.Table = table
+		out, err := ConvertTable(tc)
+		if err != nil {
+			t.Fatalf("ConvertTable failed, table:%s, err:%v", table, err)
+		}
+		expectation = append(expectation, &TableOutput{Table: table, Output: out})
+	}
+
+	for _, workers := range []int{0, 1, 3, 8, 32} {
+		output, err := ConvertTables(cc, tables, workers)
+		if err != nil {
+			t.Fatalf("ConvertTables failed, workers:%d, err:%v", workers, err)
+		}
+		if !reflect.DeepEqual(output, expectation) {
+			t.Errorf("ConvertTables failed, workers:%d, output is not deterministic", workers)
+		}
+	}
+
+	if _, err = ConvertTables(cc, []string{"table_0", "product"}, 2); err == nil {
+		t.Error("ConvertTables should fail, table product is not in snapshot file")
+	}
+}
+
+func TestConvertView(t *testing.T) {
+	snapshot := &Snapshot{
+		Version:  SchemaVersion,
+		Database: "database",
+		Tables: []*TableSchema{
+			{
+				Name: "user",
+				Columns: []*ColumnInfo{
+					{Name: "id", DataType: "int", Type: "int", Position: 1, IsPrimaryKey: true, IsAutoIncrement: true},
+					{Name: "name", DataType: "varchar", Type: "varchar(32)", Length: 32, Position: 2},
+				},
+				Indexes: []*IndexInfo{},
+			},
+			{
+				Name: "user_view",
+				Columns: []*ColumnInfo{
+					{Name: "id", DataType: "int", Type: "int", Position: 1, IsPrimaryKey: true},
+					{Name: "name", DataType: "varchar", Type: "varchar(32)", Length: 32, Position: 2},
+				},
+				Indexes:    []*IndexInfo{},
+				IsView:     true,
+				Definition: "select `user`.`id` AS `id`,\n`user`.`name` AS `name` from `user`",
+			},
+		},
+	}
+
+	dir := t.TempDir()
+	b, err := MarshalValue(snapshot, ConfigFormatJSON)
+	if err != nil {
+		t.Fatalf("MarshalValue failed, err:%v", err)
+	}
+	snapshotFile := filepath.Join(dir, "grom.snapshot.json")
+	if err = os.WriteFile(snapshotFile, b, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for views, expectation := range map[string][]string{
+		"":           {"user", "user_view"},
+		ViewsInclude: {"user", "user_view"},
+		ViewsExclude: {"user"},
+		ViewsOnly:    {"user_view"},
+	} {
+		output, err := MatchTables(&CmdConfig{SnapshotFile: snapshotFile, Views: views}, nil, nil)
+		if err != nil {
+			t.Fatalf("MatchTables failed, views:%s, err:%v", views, err)
+		}
+		if !reflect.DeepEqual(output, expectation) {
+			t.Errorf("MatchTables failed, views:%s, expectation:%s, output:%s", views, expectation, output)
+		}
+	}
+
+	cc := CmdConfig{
+		DBConfig:     DBConfig{Table: "user_view"},
+		SnapshotFile: snapshotFile, EnableInitialism: true, EnableGormV2Tag: true, EnableViewDefinition: true,
+	}
+	out, err := ConvertTable(cc)
+	if err != nil {
+		t.Fatalf("ConvertTable failed, err:%v", err)
+	}
+	for _, s := range []string{
+		"// UserView is read-only, which is mapped to the view user_view.\n//\n// View definition:\n//\n" +
+			"//\tselect `user`.`id` AS `id`,\n//\t`user`.`name` AS `name` from `user`\n",
+		"`gorm:\"->;column:id;type:int;not null\"`",
+		"`gorm:\"->;column:name;type:varchar(32);not null\"`",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("ConvertTable failed, %q is not found in output:\n%s", s, out)
+		}
+	}
+
+	cc.EnableGormV2Tag, cc.EnableXormTag, cc.EnableViewDefinition = false, true, false
+	out, err = ConvertTable(cc)
+	if err != nil {
+		t.Fatalf("ConvertTable failed, err:%v", err)
+	}
+	if !strings.Contains(out, "`xorm:\"<- int 'id' notnull\"`") || strings.Contains(out, "View definition") {
+		t.Errorf("ConvertTable failed, output:\n%s", out)
+	}
+}
+
+func TestParseColumnExtra(t *testing.T) {
+	cases := []struct {
+		extra              string
+		generated          string
+		isDefaultGenerated bool
+		isInvisible        bool
+	}{
+		{"auto_increment", "", false, false},
+		{"VIRTUAL GENERATED", GeneratedVirtual, false, false},
+		{"STORED GENERATED INVISIBLE", GeneratedStored, false, true},
+		{"DEFAULT_GENERATED on update CURRENT_TIMESTAMP", "", true, false},
+		{"auto_increment INVISIBLE", "", false, true},
+	}
+
+	for _, c := range cases {
+		generated, isDefaultGenerated, isInvisible := parseColumnExtra(c.extra)
+		if generated != c.generated || isDefaultGenerated != c.isDefaultGenerated || isInvisible != c.isInvisible {
+			t.Errorf("parseColumnExtra failed, extra:%s, output:%s,%t,%t", c.extra, generated, isDefaultGenerated, isInvisible)
+		}
+	}
+}
+
+func TestParseCheckRules(t *testing.T) {
+	cases := []struct {
+		clause      string
+		column      string
+		expectation []string
+	}{
+		{"(`age` >= 18)", "age", []string{"gte=18"}},
+		{"((`age` > 0) and (`age` <= 150))", "age", []string{"gt=0", "lte=150"}},
+		{"(0 < `age`)", "age", []string{"gt=0"}},
+		{"(`age` between 1 and 120)", "age", []string{"gte=1", "lte=120"}},
+		{"((`age` between 1 and 120) and (`age` <> 100))", "age", []string{"gte=1", "lte=120", "ne=100"}},
+		{"(`status` in (_utf8mb4'active',_utf8mb4'banned'))", "status", []string{"oneof=active banned"}},
+		{"(`level` in (1,2,3))", "level", []string{"oneof=1 2 3"}},
+		{"(char_length(`name`) <= 10)", "name", []string{"max=10"}},
+		{"(`age` regexp _utf8mb4'^[0-9]+$')", "age", nil},
+		{"((`age` > 0) or (`age` is null))", "age", nil},
+	}
+
+	for _, c := range cases {
+		output := parseCheckRules(c.clause, c.column)
+		if !reflect.DeepEqual(output, c.expectation) {
+			t.Errorf("parseCheckRules failed, clause:%s, expectation:%q, output:%q", c.clause, c.expectation, output)
+		}
+	}
+}
+
+func TestConvertGeneratedColumns(t *testing.T) {
+	dir := t.TempDir()
+	schemaFile := filepath.Join(dir, "schema.json")
+	content := `{
+    "version": 1,
+    "table": "person",
+    "columns": [
+        {"name": "id", "data_type": "int", "type": "int", "position": 1, "is_primary_key": true, "is_auto_increment": true},
+        {"name": "age", "data_type": "int", "type": "int", "position": 2},
+        {"name": "birth_year", "data_type": "int", "type": "int", "position": 3, "is_nullable": true,
+            "generated": "VIRTUAL", "generation_expression": "(year(curdate()) - ` + "`age`" + `)", "comment": "birth year"},
+        {"name": "created_at", "data_type": "datetime", "type": "datetime", "default": "CURRENT_TIMESTAMP", "position": 4,
+            "is_default_generated": true, "is_invisible": true}
+    ],
+    "indexes": [],
+    "checks": [
+        {"name": "chk_age", "clause": "(` + "`age`" + ` between 0 and 150)"},
+        {"name": "chk_birth", "clause": "(` + "`birth_year` > `age`" + `)"}
+    ]
+}`
+	if err := os.WriteFile(schemaFile, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cc := CmdConfig{SchemaFile: schemaFile, EnableFieldComment: true, EnableGormV2Tag: true, EnableValidateTag: true}
+	out, err := ConvertTable(cc)
+	if err != nil {
+		t.Fatalf("ConvertTable failed, err:%v", err)
+	}
+	for _, s := range []string{
+		"// Check constraints:\n//\n//\tchk_age: (`age` between 0 and 150)\n//\tchk_birth: (`birth_year` > `age`)\n",
+		"`gorm:\"column:age;type:int;not null\" validate:\"required,min=-2147483648,max=2147483647,gte=0,lte=150\"`",
+		"gorm:\"->;column:birth_year;type:int;comment:birth year\"",
+		"// birth year, generated always as (year(curdate()) - `age`) virtual\n",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("ConvertTable failed, %q is not found in output:\n%s", s, out)
+		}
+	}
+
+	cc.EnableGormV2Tag, cc.EnableXormTag = false, true
+	cc.ValidateTag.DisabledRules = []string{ValidateRuleCheck}
+	out, err = ConvertTable(cc)
+	if err != nil {
+		t.Fatalf("ConvertTable failed, err:%v", err)
+	}
+	if !strings.Contains(out, "xorm:\"<- int 'birth_year' comment('birth year')\"") ||
+		!strings.Contains(out, "validate:\"required,min=-2147483648,max=2147483647\"") {
+		t.Errorf("ConvertTable failed, output:\n%s", out)
+	}
+}
+
+func TestParseColumnDefault(t *testing.T) {
+	cases := []struct {
+		ci          *ColumnInfo
+		expectation columnDefault
+	}{
+		{&ColumnInfo{Default: ""}, columnDefault{kind: defaultNone}},
+		{&ColumnInfo{Default: "NULL"}, columnDefault{kind: defaultNull, value: "NULL"}},
+		{&ColumnInfo{Default: "0"}, columnDefault{kind: defaultLiteral, value: "0"}},
+		{&ColumnInfo{Default: "it's"}, columnDefault{kind: defaultLiteral, value: "it's"}},
+		{&ColumnInfo{Default: "'it''s'"}, columnDefault{kind: defaultLiteral, value: "it's"}},
+		{&ColumnInfo{DataType: "timestamp", Default: "CURRENT_TIMESTAMP"}, columnDefault{kind: defaultCurrentTimestamp, value: "CURRENT_TIMESTAMP"}},
+		{&ColumnInfo{DataType: "datetime", Default: "current_timestamp(3)"}, columnDefault{kind: defaultCurrentTimestamp, value: "CURRENT_TIMESTAMP(3)"}},
+		{&ColumnInfo{DataType: "datetime", Default: "now()"}, columnDefault{kind: defaultCurrentTimestamp, value: "CURRENT_TIMESTAMP"}},
+		{&ColumnInfo{DataType: "varchar", Default: "now"}, columnDefault{kind: defaultLiteral, value: "now"}},
+		{&ColumnInfo{DataType: "varchar", Default: "'localtime'"}, columnDefault{kind: defaultLiteral, value: "localtime"}},
+		{&ColumnInfo{DataType: "datetime", Default: "'now'"}, columnDefault{kind: defaultLiteral, value: "now"}},
+		{&ColumnInfo{Default: "uuid()", IsDefaultGenerated: true}, columnDefault{kind: defaultExpression, value: "(uuid())"}},
+		{&ColumnInfo{Default: "(rand() * 10)", IsDefaultGenerated: true}, columnDefault{kind: defaultExpression, value: "(rand() * 10)"}},
+		{&ColumnInfo{Default: "(none)"}, columnDefault{kind: defaultLiteral, value: "(none)"}},
+		{&ColumnInfo{DataType: "bit", Default: "b'1'"}, columnDefault{kind: defaultExpression, value: "(b'1')"}},
+		{&ColumnInfo{DataType: "varchar", Default: "b'1'"}, columnDefault{kind: defaultLiteral, value: "b'1'"}},
+	}
+
+	for _, c := range cases {
+		output := parseColumnDefault(c.ci)
+		if output != c.expectation {
+			t.Errorf("parseColumnDefault failed, default:%s, expectation:%+v, output:%+v", c.ci.Default, c.expectation, output)
+		}
+	}
+}
+
+func TestGetORMDefaults(t *testing.T) {
+	cases := []struct {
+		ci     *ColumnInfo
+		gorm   string
+		gormV2 string
+		xorm   string
+		beego  string
+	}{
+		{&ColumnInfo{DataType: "int", Default: ""}, "", "", "", ""},
+		{&ColumnInfo{DataType: "varchar", Default: "NULL"}, "", "null", "", ""},
+		{&ColumnInfo{DataType: "int", Default: "10"}, "10", "10", "10", "10"},
+		{&ColumnInfo{DataType: "decimal", Default: "-1.50"}, "-1.50", "-1.50", "-1.50", "-1.50"},
+		{&ColumnInfo{DataType: "varchar", Default: "10"}, "'10'", "'10'", "'10'", "10"},
+		{&ColumnInfo{DataType: "varchar", Default: "it's"}, "'it''s'", "'it''s'", "'it''s'", "it's"},
+		{&ColumnInfo{DataType: "varchar", Default: "a;b"}, "", `'a\;b'`, "'a;b'", ""},
+		{&ColumnInfo{DataType: "varchar", Default: "a,b"}, "'a,b'", "'a,b'", "", "a,b"},
+		{&ColumnInfo{DataType: "varchar", Default: "(a)"}, "'(a)'", "'(a)'", "", ""},
+		{&ColumnInfo{DataType: "varchar", Default: "(a)", IsDefaultGenerated: true}, "(a)", "(a)", "(a)", ""},
+		{&ColumnInfo{DataType: "datetime", Default: "CURRENT_TIMESTAMP"}, "CURRENT_TIMESTAMP", "CURRENT_TIMESTAMP", "CURRENT_TIMESTAMP", ""},
+		{&ColumnInfo{DataType: "varchar", Default: "now"}, "'now'", "'now'", "'now'", "now"},
+		{&ColumnInfo{DataType: "datetime", Default: "current_timestamp(6)"}, "CURRENT_TIMESTAMP(6)", "CURRENT_TIMESTAMP(6)", "CURRENT_TIMESTAMP(6)", ""},
+		{&ColumnInfo{DataType: "varchar", Default: "uuid()", IsDefaultGenerated: true}, "(uuid())", "(uuid())", "(uuid())", ""},
+		{&ColumnInfo{DataType: "json", Default: "json_array(1,2)", IsDefaultGenerated: true}, "(json_array(1,2))", "(json_array(1,2))", "", ""},
+	}
+
+	for _, c := range cases {
+		if output := getGormDefault(c.ci); output != c.gorm {
+			t.Errorf("getGormDefault failed, default:%s, expectation:%s, output:%s", c.ci.Default, c.gorm, output)
+		}
+		if output := getGormV2Default(c.ci); output != c.gormV2 {
+			t.Errorf("getGormV2Default failed, default:%s, expectation:%s, output:%s", c.ci.Default, c.gormV2, output)
+		}
+		if output := getXormDefault(c.ci); output != c.xorm {
+			t.Errorf("getXormDefault failed, default:%s, expectation:%s, output:%s", c.ci.Default, c.xorm, output)
+		}
+		if output := getBeegoDefault(c.ci); output != c.beego {
+			t.Errorf("getBeegoDefault failed, default:%s, expectation:%s, output:%s", c.ci.Default, c.beego, output)
+		}
+	}
+}
+
+func TestGetGoDefault(t *testing.T) {
+	cases := []struct {
+		ci          *ColumnInfo
+		fieldType   string
+		expectation string
+	}{
+		{&ColumnInfo{DataType: "varchar", Default: `say "hi"`}, GoString, `"say \"hi\""`},
+		{&ColumnInfo{DataType: "int", Default: "10"}, GoInt, "10"},
+		{&ColumnInfo{DataType: "int", Default: "-1"}, GoUint, ""},
+		{&ColumnInfo{DataType: "double", Default: "1.5"}, GoFloat64, "1.5"},
+		{&ColumnInfo{DataType: "tinyint", Default: "1"}, GoBool, "true"},
+		{&ColumnInfo{DataType: "blob", Default: "abc"}, GoBytes, `[]byte("abc")`},
+		{&ColumnInfo{DataType: "bit", Default: "b'1'"}, GoBytes, ""},
+		{&ColumnInfo{DataType: "datetime", Default: "CURRENT_TIMESTAMP"}, GoTime, ""},
+		{&ColumnInfo{DataType: "varchar", Default: "now"}, GoString, `"now"`},
+		{&ColumnInfo{DataType: "varchar", Default: "NULL"}, SQLNullString, ""},
+		{&ColumnInfo{DataType: "varchar", Default: "a"}, SQLNullString, `sql.NullString{String: "a", Valid: true}`},
+		{&ColumnInfo{DataType: "int", Default: "1"}, SQLNullInt32, "sql.NullInt32{Int32: 1, Valid: true}"},
+		{&ColumnInfo{DataType: "tinyint", Default: "0"}, SQLNullBool, "sql.NullBool{Bool: false, Valid: true}"},
+		{&ColumnInfo{DataType: "varchar", Default: "a"}, GureguNullString, `null.StringFrom("a")`},
+		{&ColumnInfo{DataType: "bigint", Default: "1"}, GureguNullInt, "null.IntFrom(1)"},
+		{&ColumnInfo{DataType: "varchar", Default: "uuid()", IsDefaultGenerated: true}, GoString, ""},
+	}
+
+	for _, c := range cases {
+		output := getGoDefault(c.ci, c.fieldType)
+		if output != c.expectation {
+			t.Errorf("getGoDefault failed, default:%s, type:%s, expectation:%s, output:%s", c.ci.Default, c.fieldType, c.expectation, output)
+		}
+	}
+}
+
+func TestGenerateConstructor(t *testing.T) {
+	dir := t.TempDir()
+	schemaFile := filepath.Join(dir, "schema.json")
+	content := `{
+    "version": 1,
+    "table": "user",
+    "columns": [
+        {"name": "id", "data_type": "int", "type": "int", "position": 1, "is_primary_key": true, "is_auto_increment": true},
+        {"name": "name", "data_type": "varchar", "type": "varchar(20)", "default": "it's", "position": 2},
+        {"name": "level", "data_type": "int", "type": "int", "default": "1", "position": 3},
+        {"name": "code", "data_type": "varchar", "type": "varchar(36)", "default": "uuid()", "position": 4,
+            "is_default_generated": true},
+        {"name": "created_at", "data_type": "datetime", "type": "datetime(3)", "default": "CURRENT_TIMESTAMP(3)", "position": 5,
+            "is_default_generated": true}
+    ],
+    "indexes": []
+}`
+	if err := os.WriteFile(schemaFile, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cc := CmdConfig{SchemaFile: schemaFile, EnableGormV2Tag: true, EnableConstructor: true}
+	out, err := ConvertTable(cc)
+	if err != nil {
+		t.Fatalf("ConvertTable failed, err:%v", err)
+	}
+	for _, s := range []string{
+		"gorm:\"column:name;type:varchar(20);not null;default:'it''s'\"",
+		"gorm:\"column:code;type:varchar(36);not null;default:(uuid())\"",
+		"gorm:\"column:created_at;type:datetime(3);not null;default:CURRENT_TIMESTAMP(3);autoCreateTime\"",
+		"func NewUser() *User {\n\treturn &User{\n\t\tName:  \"it's\",\n\t\tLevel: 1,\n\t}\n}\n",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("ConvertTable failed, %q is not found in output:\n%s", s, out)
+		}
+	}
+
+	cc.EnableConstructor = false
+	out, err = ConvertTable(cc)
+	if err != nil {
+		t.Fatalf("ConvertTable failed, err:%v", err)
+	}
+	if strings.Contains(out, "NewUser") {
+		t.Errorf("ConvertTable failed, output:\n%s", out)
+	}
+}
+
+func TestEscapeTagComments(t *testing.T) {
+	cases := []struct {
+		comment string
+		gorm    string
+		gormV2  string
+		xorm    string
+		beego   string
+	}{
+		{"user name", "user name", "user name", "user name", "user name"},
+		{"it's", "it''s", "it's", "it''s", "it''s"},
+		{"a;b", "a b", `a\;b`, "a;b", "a b"},
+		{"a, b", "a, b", "a, b", "a b", "a, b"},
+		{"name (nick)", "name (nick)", "name (nick)", "name (nick)", "name nick"},
+		{"line one\r\nline two\n\tline three", "line one line two line three", "line one line two line three",
+			"line one line two line three", "line one line two line three"},
+		{"\"quoted\" `raw` b\\c", "\"quoted\" `raw` b\\c", "\"quoted\" `raw` b\\c", "\"quoted\" `raw` b\\c", "\"quoted\" `raw` b\\c"},
+	}
+
+	for _, c := range cases {
+		if output := getGormComment(c.comment); output != c.gorm {
+			t.Errorf("getGormComment failed, comment:%q, expectation:%q, output:%q", c.comment, c.gorm, output)
+		}
+		if output := getGormV2Comment(c.comment); output != c.gormV2 {
+			t.Errorf("getGormV2Comment failed, comment:%q, expectation:%q, output:%q", c.comment, c.gormV2, output)
+		}
+		if output := getXormComment(c.comment); output != c.xorm {
+			t.Errorf("getXormComment failed, comment:%q, expectation:%q, output:%q", c.comment, c.xorm, output)
+		}
+		if output := getBeegoComment(c.comment); output != c.beego {
+			t.Errorf("getBeegoComment failed, comment:%q, expectation:%q, output:%q", c.comment, c.beego, output)
+		}
+	}
+}
+
+func TestCommentLines(t *testing.T) {
+	cases := []struct {
+		comment     string
+		expectation []string
+	}{
+		{"", nil},
+		{"user name", []string{"user name"}},
+		{"line one\r\nline two\rline three\n\n\tline four ", []string{"line one", "line two", "line three", "line four"}},
+		{"nul\x00 and bom\ufeff", []string{"nul  and bom"}},
+	}
+
+	for _, c := range cases {
+		output := commentLines(c.comment)
+		if !reflect.DeepEqual(output, c.expectation) {
+			t.Errorf("commentLines failed, comment:%q, expectation:%q, output:%q", c.comment, c.expectation, output)
+		}
+	}
+}
+
+func TestConvertAdversarialComments(t *testing.T) {
+	dir := t.TempDir()
+	schemaFile := filepath.Join(dir, "schema.json")
+	content := `{
+    "version": 1,
+    "table": "user",
+    "comment": "user info\nwith ` + "`backquotes`" + ` and */",
+    "columns": [
+        {"name": "id", "data_type": "int", "type": "int", "position": 1, "is_primary_key": true, "is_auto_increment": true,
+            "comment": "id\\"},
+        {"name": "name", "data_type": "varchar", "type": "varchar(20)", "position": 2,
+            "comment": "it's; \"quoted\" ` + "`raw`" + `, (a) b\\c"},
+        {"name": "bio", "data_type": "text", "type": "text", "position": 3, "is_nullable": true,
+            "comment": "line one\r\nline two\n\n\tline three\u0000"}
+    ],
+    "indexes": []
+}`
+	if err := os.WriteFile(schemaFile, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	lookup := func(out string, keys []string) map[string]map[string]string {
+		file, err := parser.ParseFile(token.NewFileSet(), "model.go", out, parser.ParseComments)
+		if err != nil {
+			t.Fatalf("parser.ParseFile failed, err:%v, output:\n%s", err, out)
+		}
+		tags := make(map[string]map[string]string)
+		ast.Inspect(file, func(n ast.Node) bool {
+			field, ok := n.(*ast.Field)
+			if !ok || field.Tag == nil {
+				return true
+			}
+			tag, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				t.Fatalf("strconv.Unquote failed, err:%v, tag:%s", err, field.Tag.Value)
+			}
+			values := make(map[string]string)
+			for _, key := range keys {
+				value, ok := reflect.StructTag(tag).Lookup(key)
+				if !ok {
+					t.Errorf("reflect.StructTag.Lookup failed, key:%s, tag:%s", key, tag)
+				}
+				values[key] = value
+			}
+			tags[field.Names[0].Name] = values
+			return true
+		})
+		return tags
+	}
+
+	cc := CmdConfig{SchemaFile: schemaFile, EnableFieldComment: true, EnableJSONTag: true,
+		EnableGormTag: true, EnableXormTag: true, EnableBeegoTag: true}
+	out, err := ConvertTable(cc)
+	if err != nil {
+		t.Fatalf("ConvertTable failed, err:%v", err)
+	}
+	tags := lookup(out, []string{"json", "gorm", "xorm", "orm"})
+	expectations := map[string]map[string]string{
+		"Id": {
+			"gorm": `primary_key;column:id;type:int auto_increment;comment:'id\'`,
+			"xorm": `pk autoincr int 'id' comment('id\')`,
+			"orm":  `pk;auto;column(id);type(int);size(1);description(id\)`,
+		},
+		"Name": {
+			"gorm": "column:name;type:varchar(20);not null;comment:'it''s \"quoted\" `raw`, (a) b\\c'",
+			"xorm": "varchar(20) 'name' notnull comment('it''s; \"quoted\" `raw` (a) b\\c')",
+			"orm":  "column(name);type(varchar);size(0);description(it''s \"quoted\" `raw`, a b\\c)",
+		},
+		"Bio": {
+			"gorm": "column:bio;type:text;comment:'line one line two line three'",
+			"xorm": "text 'bio' comment('line one line two line three')",
+			"orm":  "column(bio);type(text);null;description(line one line two line three)",
+		},
+	}
+	for name, values := range expectations {
+		for key, value := range values {
+			if tags[name][key] != value {
+				t.Errorf("ConvertTable failed, field:%s, key:%s, expectation:%s, output:%s", name, key, value, tags[name][key])
+			}
+		}
+	}
+	for _, s := range []string{
+		"// User user info\n// with `backquotes` and */\ntype User struct {\n",
+		"\t// line one\n\t// line two\n\t// line three\n\tBio string ",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("ConvertTable failed, %q is not found in output:\n%s", s, out)
+		}
+	}
+
+	cc.EnableGormTag, cc.EnableXormTag, cc.EnableBeegoTag, cc.EnableGormV2Tag = false, false, false, true
+	out, err = ConvertTable(cc)
+	if err != nil {
+		t.Fatalf("ConvertTable failed, err:%v", err)
+	}
+	tags = lookup(out, []string{"json", "gorm"})
+	expectation := "column:name;type:varchar(20);not null;comment:it's\\; \"quoted\" `raw`, (a) b\\c"
+	if tags["Name"]["gorm"] != expectation {
+		t.Errorf("ConvertTable failed, expectation:%s, output:%s", expectation, tags["Name"]["gorm"])
+	}
+}
+
+func TestConvertConventions(t *testing.T) {
+	dir := t.TempDir()
+	schemaFile := filepath.Join(dir, "schema.json")
+	content := `{
+    "version": 1,
+    "table": "user",
+    "columns": [
+        {"name": "id", "data_type": "bigint", "type": "bigint unsigned", "position": 1, "is_primary_key": true,
+            "is_auto_increment": true, "is_unsigned": true},
+        {"name": "created_at", "data_type": "datetime", "type": "datetime", "position": 2},
+        {"name": "updated_at", "data_type": "int", "type": "int", "position": 3},
+        {"name": "deleted_at", "data_type": "datetime", "type": "datetime", "position": 4, "is_nullable": true},
+        {"name": "version", "data_type": "varchar", "type": "varchar(10)", "position": 5},
+        {"name": "revision", "data_type": "int", "type": "int", "default": "0", "position": 6}
+    ],
+    "indexes": []
+}`
+	if err := os.WriteFile(schemaFile, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cc := CmdConfig{SchemaFile: schemaFile, EnableGormV2Tag: true, EnableValidateTag: true}
+	out, err := ConvertTable(cc)
+	if err != nil {
+		t.Fatalf("ConvertTable failed, err:%v", err)
+	}
+	for _, s := range []string{
+		"import (\n\t\"time\"\n\n\t\"gorm.io/gorm\"\n)\n",
+		"CreatedAt time.Time      `gorm:\"column:created_at;type:datetime;not null;autoCreateTime\"`",
+		"UpdatedAt int            `gorm:\"column:updated_at;type:int;not null;autoUpdateTime\" validate:\"min=-2147483648,max=2147483647\"`",
+		"DeletedAt gorm.DeletedAt `gorm:\"column:deleted_at;type:datetime\"`",
+		"Version   string         `gorm:\"column:version;type:varchar(10);not null\" validate:\"required\"`",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("ConvertTable failed, %q is not found in output:\n%s", s, out)
+		}
+	}
+
+	cc.EnableGormV2Tag, cc.EnableXormTag, cc.EnableBeegoTag, cc.EnableValidateTag = false, true, true, false
+	cc.Conventions = ConventionConfig{
+		DisabledRules: []string{ConventionUpdatedAt},
+		Columns:       map[string][]string{ConventionVersion: {"revision"}},
+	}
+	out, err = ConvertTable(cc)
+	if err != nil {
+		t.Fatalf("ConvertTable failed, err:%v", err)
+	}
+	for _, s := range []string{
+		"xorm:\"datetime 'created_at' notnull created\" orm:\"column(created_at);type(datetime);auto_now_add\"",
+		"xorm:\"int 'updated_at' notnull\" orm:\"column(updated_at);type(int);size(1)\"",
+		"xorm:\"datetime 'deleted_at' deleted\" orm:\"column(deleted_at);type(datetime);null\"",
+		"xorm:\"int 'revision' notnull version default(0)\"",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("ConvertTable failed, %q is not found in output:\n%s", s, out)
+		}
+	}
+}
+
+func TestConvertSoftDeleteFlag(t *testing.T) {
+	dir := t.TempDir()
+	schemaFile := filepath.Join(dir, "schema.json")
+	content := `{
+    "version": 1,
+    "table": "user",
+    "columns": [
+        {"name": "id", "data_type": "int", "type": "int", "position": 1, "is_primary_key": true, "is_auto_increment": true},
+        {"name": "deleted_at", "data_type": "datetime", "type": "datetime", "position": 2, "is_nullable": true},
+        {"name": "is_deleted", "data_type": "tinyint", "type": "tinyint(1)", "default": "0", "position": 3}
+    ],
+    "indexes": []
+}`
+	if err := os.WriteFile(schemaFile, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cc := CmdConfig{SchemaFile: schemaFile, EnableGormV2Tag: true, EnableSQLNull: true}
+	out, err := ConvertTable(cc)
+	if err != nil {
+		t.Fatalf("ConvertTable failed, err:%v", err)
+	}
+	for _, s := range []string{
+		"import (\n\t\"database/sql\"\n\n\t\"gorm.io/plugin/soft_delete\"\n)\n",
+		"DeletedAt sql.NullTime          `gorm:\"column:deleted_at;type:datetime\"`",
+		"IsDeleted soft_delete.DeletedAt `gorm:\"column:is_deleted;type:tinyint(1);not null;default:0;softDelete:flag,DeletedAtField:DeletedAt\"`",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("ConvertTable failed, %q is not found in output:\n%s", s, out)
+		}
+	}
+
+	cc.Conventions.DisabledRules = []string{ConventionIsDeleted}
+	out, err = ConvertTable(cc)
+	if err != nil {
+		t.Fatalf("ConvertTable failed, err:%v", err)
+	}
+	if !strings.Contains(out, "DeletedAt gorm.DeletedAt `gorm:\"column:deleted_at;type:datetime\"`") ||
+		!strings.Contains(out, "IsDeleted bool           `gorm:\"column:is_deleted;type:tinyint(1);not null;default:0\"`") {
+		t.Errorf("ConvertTable failed, output:\n%s", out)
+	}
+}
+
+func TestGetImports(t *testing.T) {
+	cases := []struct {
+		types       []string
+		expectation []string
+	}{
+		{[]string{GoInt, GoString}, nil},
+		{[]string{GoTime, GoPointerTime, SQLNullString}, []string{"database/sql", "time"}},
+		{[]string{GureguNullTime, GoTime}, []string{"time", "", "gopkg.in/guregu/null.v4"}},
+		{[]string{SoftDeleteDeletedAt, GormDeletedAt}, []string{"gorm.io/gorm", "gorm.io/plugin/soft_delete"}},
+	}
+
+	for _, c := range cases {
+		fields := make([]*StructField, 0, len(c.types))
+		for _, typ := range c.types {
+			fields = append(fields, &StructField{Type: typ})
+		}
+		if output := getImports(fields, importPaths); !reflect.DeepEqual(output, c.expectation) {
+			t.Errorf("getImports failed, types:%q, expectation:%q, output:%q", c.types, c.expectation, output)
+		}
+	}
+}
+
+func TestEmbedBaseModel(t *testing.T) {
+	newFields := func(types ...string) []*StructField {
+		fields := make([]*StructField, 0, len(types)/2)
+		for i := 0; i < len(types); i += 2 {
+			fields = append(fields, &StructField{Name: convertName(types[i], nil), RawName: types[i], Type: types[i+1]})
+		}
+		return fields
+	}
+
+	cases := []struct {
+		bc          BaseModelConfig
+		fields      []*StructField
+		expectation []string
+		reason      string
+	}{
+		{
+			BaseModelConfig{Name: "gorm.Model"},
+			newFields("name", GoString, "id", GoUint64, "created_at", GoTime, "updated_at", GoTime, "deleted_at", GormDeletedAt),
+			[]string{"Name", "gorm.Model"},
+			"",
+		},
+		{
+			BaseModelConfig{Name: "gorm.Model"},
+			newFields("id", GoUint, "created_at", GoTime, "updated_at", GoTime, "deleted_at", GoTime),
+			[]string{"Id", "CreatedAt", "UpdatedAt", "DeletedAt"},
+			"column deleted_at is time.Time instead of gorm.DeletedAt",
+		},
+		{
+			BaseModelConfig{Name: "gorm.Model"},
+			newFields("id", GoInt, "created_at", GoTime),
+			[]string{"Id", "CreatedAt"},
+			"missing column deleted_at",
+		},
+		{
+			BaseModelConfig{Name: "Base", Columns: map[string]string{"id": GoInt64, "created_at": GoTime}},
+			newFields("id", GoInt, "name", GoString, "created_at", GoTime),
+			[]string{"Base", "Name"},
+			"",
+		},
+	}
+
+	for _, c := range cases {
+		fields, reason := embedBaseModel(&c.bc, c.fields)
+		var output []string
+		for _, field := range fields {
+			if field.IsEmbedded {
+				output = append(output, field.Type)
+			} else {
+				output = append(output, field.Name)
+			}
+		}
+		if !reflect.DeepEqual(output, c.expectation) || reason != c.reason {
+			t.Errorf("embedBaseModel failed, expectation:%q %s, output:%q %s", c.expectation, c.reason, output, reason)
+		}
+	}
+}
+
+func TestConvertBaseModel(t *testing.T) {
+	dir := t.TempDir()
+	schemaFile := filepath.Join(dir, "schema.json")
+	content := `{
+    "version": 1,
+    "table": "user",
+    "columns": [
+        {"name": "id", "data_type": "bigint", "type": "bigint", "position": 1, "is_primary_key": true, "is_auto_increment": true},
+        {"name": "name", "data_type": "varchar", "type": "varchar(20)", "position": 2},
+        {"name": "base", "data_type": "varchar", "type": "varchar(20)", "position": 3},
+        {"name": "created_at", "data_type": "datetime", "type": "datetime", "position": 4}
+    ],
+    "indexes": []
+}`
+	if err := os.WriteFile(schemaFile, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cc := CmdConfig{SchemaFile: schemaFile, EnableJSONTag: true, BaseModel: BaseModelConfig{
+		Name: "model.Base", Import: "example.com/app/model", Columns: map[string]string{"id": GoInt64, "created_at": GoTime},
+	}}
+	out, err := ConvertTable(cc)
+	if err != nil {
+		t.Fatalf("ConvertTable failed, err:%v", err)
+	}
+	expectation := `package model
+
+import (
+	"example.com/app/model"
+)
+
+// User
+type User struct {
+	model.Base
+	Name  string ` + "`json:\"name\"`" + `
+	Base2 string ` + "`json:\"base\"`" + `
+}`
+	if out != expectation {
+		t.Errorf("ConvertTable failed, expectation:\n%s\noutput:\n%s", expectation, out)
+	}
+
+	cc.BaseModel.Columns["updated_at"] = GoTime
+	out, err = ConvertTable(cc)
+	if err != nil {
+		t.Fatalf("ConvertTable failed, err:%v", err)
+	}
+	if strings.Contains(out, "model.Base") || !strings.Contains(out, "CreatedAt time.Time `json:\"created_at\"`") {
+		t.Errorf("ConvertTable failed, output:\n%s", out)
+	}
+}
+
+func TestFilterColumns(t *testing.T) {
+	cc := &CmdConfig{
+		IncludeColumns: []string{"user.id", "user.name", "user.age", "order.id"},
+		ExcludeColumns: []string{"user.age", "user.unknown"},
+		RenameColumns:  map[string]string{"user.name": "UserName", "order.name": "OrderName"},
+	}
+	tc := getTableColumns(cc, "user")
+	cis := []*ColumnInfo{{Name: "id"}, {Name: "name"}, {Name: "age"}, {Name: "remark"}}
+	if unknown := getUnknownColumns(tc, cis); !reflect.DeepEqual(unknown, []string{"unknown"}) {
+		t.Errorf("getUnknownColumns failed, output:%q", unknown)
+	}
+
+	filtered := filterColumns("user", tc, cis)
+	names := getFieldNames(filtered, tc.rename, nil)
+	expectation := map[string]string{"id": "Id", "name": "UserName"}
+	if len(filtered) != 2 || !reflect.DeepEqual(names, expectation) {
+		t.Errorf("filterColumns failed, expectation:%v, output:%v", expectation, names)
+	}
+
+	tc = getTableColumns(cc, "account")
+	if filtered = filterColumns("account", tc, cis); len(filtered) != len(cis) {
+		t.Errorf("filterColumns failed, output:%d columns", len(filtered))
+	}
+}
+
+func TestConvertColumnRules(t *testing.T) {
+	dir := t.TempDir()
+	schemaFile := filepath.Join(dir, "schema.json")
+	content := `{
+    "version": 1,
+    "table": "legacy_tbl",
+    "columns": [
+        {"name": "id", "data_type": "int", "type": "int", "position": 1, "is_primary_key": true, "is_auto_increment": true},
+        {"name": "usr_nm", "data_type": "varchar", "type": "varchar(20)", "position": 2},
+        {"name": "legacy_flag", "data_type": "tinyint", "type": "tinyint", "position": 3}
+    ],
+    "indexes": [
+        {"name": "idx_usr_nm", "column_name": "usr_nm", "sequence": 1},
+        {"name": "idx_legacy_flag", "column_name": "legacy_flag", "sequence": 1}
+    ]
+}`
+	if err := os.WriteFile(schemaFile, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cc := CmdConfig{
+		SchemaFile: schemaFile, EnableJSONTag: true, EnableBeegoTag: true,
+		ExcludeColumns: []string{"legacy_tbl.legacy_flag"},
+		RenameColumns:  map[string]string{"legacy_tbl.usr_nm": "UserName"},
+	}
+	out, err := ConvertTable(cc)
+	if err != nil {
+		t.Fatalf("ConvertTable failed, err:%v", err)
+	}
+	for _, s := range []string{
+		"UserName string `json:\"usr_nm\" orm:\"column(usr_nm);",
+		`{"UserName"}`,
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("ConvertTable failed, expectation:%s, output:\n%s", s, out)
+		}
+	}
+	for _, s := range []string{"legacy_flag", "LegacyFlag"} {
+		if strings.Contains(out, s) {
+			t.Errorf("ConvertTable failed, unexpected:%s, output:\n%s", s, out)
+		}
+	}
+}
+
+func TestGetDBPerTarget(t *testing.T) {
+	defer CloseDB()
+
+	manifestFile := filepath.Join(t.TempDir(), "grom.yaml")
+	content := `defaults:
+  host: localhost
+  user: user
+  database: database
+targets:
+  - table: order
+    output: order.go
+  - table: user
+    output: user.go
+    host: replica.local
+    port: 3307
+    user: reader
+`
+	if err := os.WriteFile(manifestFile, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	manifest, err := LoadManifest(manifestFile)
+	if err != nil {
+		t.Fatalf("LoadManifest failed, err:%v", err)
+	}
+
+	first, err := getDB(&manifest.Targets[0].Config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := getDB(&manifest.Targets[0].Config); again != first {
+		t.Error("getDB should reuse the connection of the same settings")
+	}
+	second, err := getDB(&manifest.Targets[1].Config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second == first || !strings.Contains(dbKey, "reader@tcp(replica.local:3307)") {
+		t.Errorf("getDB should open the connection of the target settings, key:%s", dbKey)
+	}
+}
+
+func TestConvertDisambiguatedIndexes(t *testing.T) {
+	dir := t.TempDir()
+	schemaFile := filepath.Join(dir, "schema.json")
+	content := `{
+    "version": 1,
+    "table": "user",
+    "columns": [
+        {"name": "id", "data_type": "int", "type": "int", "position": 1, "is_primary_key": true},
+        {"name": "user_id", "data_type": "int", "type": "int", "position": 2},
+        {"name": "userid", "data_type": "int", "type": "int", "position": 3},
+        {"name": "table_name", "data_type": "varchar", "type": "varchar(20)", "position": 4}
+    ],
+    "indexes": [
+        {"name": "idx_table_name", "column_name": "table_name", "sequence": 1},
+        {"name": "uniq_userid", "column_name": "userid", "sequence": 1, "is_unique": true}
+    ]
+}`
+	if err := os.WriteFile(schemaFile, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := ConvertTable(CmdConfig{SchemaFile: schemaFile, EnableBeegoTag: true})
+	if err != nil {
+		t.Fatalf("ConvertTable failed, err:%v", err)
+	}
+	for _, s := range []string{
+		"Userid2 ", "TableName2 ", `{"TableName2"}, // idx_table_name`, `{"Userid2"}, // uniq_userid`,
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("ConvertTable failed, expectation:%s, output:\n%s", s, out)
+		}
+	}
+}
+
+func TestGetFieldsEnableGoTime(t *testing.T) {
+	schemaFile := filepath.Join(t.TempDir(), "schema.json")
+	content := `{
+    "version": 1,
+    "table": "user",
+    "columns": [
+        {"name": "id", "data_type": "int", "type": "int", "position": 1, "is_primary_key": true},
+        {"name": "birthday", "data_type": "datetime", "type": "datetime", "position": 2}
+    ],
+    "indexes": []
+}`
+	if err := os.WriteFile(schemaFile, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cc := &CmdConfig{SchemaFile: schemaFile}
+	if _, err := GetFields(cc); err != nil {
+		t.Fatalf("GetFields failed, err:%v", err)
+	}
+	if !cc.EnableGoTime {
+		t.Error("GetFields should set EnableGoTime for the time.Time field")
+	}
+}
+
+func TestLoadManifest(t *testing.T) {
+	dir := t.TempDir()
+	manifestFile := filepath.Join(dir, "grom.yaml")
+	content := `defaults:
+  host: localhost
+  database: database
+  package_name: models
+  snapshot_file: grom.snapshot.json
+  enable_json_tag: true
+  json_tag:
+    naming: camel
+targets:
+  - table: order
+    output: order.go
+    enable_gorm_v2_tag: true
+  - tables: ["user_*"]
+    output: /tmp/user
+    package_name: user
+    json_tag:
+      omit_empty: true
+    enable_jsn_tag: true
+`
+	if err := os.WriteFile(manifestFile, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, err := LoadManifest(manifestFile)
+	if err != nil {
+		t.Fatalf("LoadManifest failed, err:%v", err)
+	}
+
+	expectation := []*ManifestTarget{
+		{
+			Config: CmdConfig{
+				DBConfig:        DBConfig{Host: "localhost", Database: "database", Table: "order"},
+				PackageName:     "models",
+				SnapshotFile:    filepath.Join(dir, "grom.snapshot.json"),
+				EnableJSONTag:   true,
+				EnableGormV2Tag: true,
+				JSONTag:         TagConfig{Naming: NamingCamel},
+			},
+			Output: filepath.Join(dir, "order.go"),
+		},
+		{
+			Config: CmdConfig{
+				DBConfig:      DBConfig{Host: "localhost", Database: "database"},
+				PackageName:   "user",
+				SnapshotFile:  filepath.Join(dir, "grom.snapshot.json"),
+				EnableJSONTag: true,
+				JSONTag:       TagConfig{Naming: NamingCamel, OmitEmpty: true},
+				unknownKeys:   []string{"enable_jsn_tag"},
+			},
+			Output: "/tmp/user",
+			Tables: []string{"user_*"},
+		},
+	}
+	if !reflect.DeepEqual(manifest.Targets, expectation) {
+		for i, target := range manifest.Targets {
+			t.Errorf("LoadManifest failed, target %d:%+v", i, *target)
+		}
+	}
+
+	for _, c := range []string{
+		"defaults:\n  host: localhost\n",
+		"targets:\n  - table: order\n",
+		"targets:\n  - table: order\n    tables: [user]\n    output: models\n",
+		"targets:\n  - output\n",
+	} {
+		if err = os.WriteFile(manifestFile, []byte(c), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = LoadManifest(manifestFile); err == nil {
+			t.Errorf("LoadManifest should fail, content:%s", c)
+		}
+	}
 }
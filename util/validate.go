@@ -0,0 +1,280 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// integerRanges represents the value ranges of mysql integer types, the first
+// range is for signed type and the second range is for unsigned type.
+var integerRanges = map[string][2][2]string{
+	"tinyint":   {{"-128", "127"}, {"0", "255"}},
+	"smallint":  {{"-32768", "32767"}, {"0", "65535"}},
+	"mediumint": {{"-8388608", "8388607"}, {"0", "16777215"}},
+	"int":       {{"-2147483648", "2147483647"}, {"0", "4294967295"}},
+	"integer":   {{"-2147483648", "2147483647"}, {"0", "4294967295"}},
+}
+
+// checkOperatorRules maps the comparison operators of the check constraints to the validator rules.
+var checkOperatorRules = map[string]string{">=": "gte", ">": "gt", "<=": "lte", "<": "lt", "=": "eq", "<>": "ne", "!=": "ne"}
+
+// checkReversedOperators maps the comparison operators to the reversed ones, such as 0 <= x to x >= 0.
+var checkReversedOperators = map[string]string{">=": "<=", ">": "<", "<=": ">=", "<": ">", "=": "=", "<>": "<>", "!=": "!="}
+
+var (
+	checkColumnRegexp      = regexp.MustCompile("`([^`]+)`")
+	checkCharsetRegexp     = regexp.MustCompile(`(?i)_[a-z0-9]+'`)
+	checkCompareRegexp     = regexp.MustCompile(`^\?\s*(>=|<=|<>|!=|>|<|=)\s*(-?[0-9]+(?:\.[0-9]+)?)$`)
+	checkReverseRegexp     = regexp.MustCompile(`^(-?[0-9]+(?:\.[0-9]+)?)\s*(>=|<=|<>|!=|>|<|=)\s*\?$`)
+	checkBetweenRegexp     = regexp.MustCompile(`(?i)^\?\s+between\s+(-?[0-9]+(?:\.[0-9]+)?)\s+and\s+(-?[0-9]+(?:\.[0-9]+)?)$`)
+	checkInRegexp          = regexp.MustCompile(`(?i)^\?\s+in\s*\((.*)\)$`)
+	checkNumberListRegexp  = regexp.MustCompile(`^-?[0-9]+(?:\.[0-9]+)?(?:\s*,\s*-?[0-9]+(?:\.[0-9]+)?)*$`)
+	checkLengthRegexp      = regexp.MustCompile(`(?i)^(?:char_length|character_length|length)\(\?\)\s*(>=|<=|>|<|=)\s*([0-9]+)$`)
+	checkLengthRuleMapping = map[string]string{">=": "min", ">": "gt", "<=": "max", "<": "lt", "=": "len"}
+)
+
+// nullStructTypes lists the nullable struct types, the validator does not apply the value rules to them.
+var nullStructTypes = []string{
+	GureguNullString, GureguNullInt, GureguNullFloat, GureguNullBool, GureguNullTime,
+	SQLNullString, SQLNullInt32, SQLNullInt64, SQLNullFloat64, SQLNullBool, SQLNullTime,
+}
+
+// getValidateTag returns the tag string of go-playground/validator, the value rules such as max, min
+// and oneof are skipped for the nullable struct types, which are not checked by the validator.
+func getValidateTag(ci *ColumnInfo, vc *ValidateTagConfig, fieldType string) string {
+	if containsString(vc.SkipColumns, ci.Name) {
+		return ""
+	}
+	if rule, ok := vc.ColumnRules[ci.Name]; ok {
+		if rule == "" {
+			return ""
+		}
+		return fmt.Sprintf("validate:%q", rule)
+	}
+
+	if containsString(nullStructTypes, fieldType) {
+		return ""
+	}
+
+	enabled := func(rule string) bool {
+		return !containsString(vc.DisabledRules, rule)
+	}
+
+	var rules []string
+	if ci.IsNullable {
+		rules = append(rules, "omitempty")
+	} else if enabled(ValidateRuleRequired) && ci.Default == "" &&
+		!ci.IsAutoIncrement && !ci.IsReadOnly && ci.Convention == "" && !isBoolColumn(ci) {
+		rules = append(rules, "required")
+	}
+
+	switch ci.DataType {
+	case "char", "varchar":
+		if enabled(ValidateRuleLength) && ci.Length > 0 {
+			rules = append(rules, fmt.Sprintf("max=%d", ci.Length))
+		}
+	case "enum":
+		if enabled(ValidateRuleEnum) {
+			if oneOf := getOneOfRule(parseEnumValues(ci.Type)); oneOf != "" {
+				rules = append(rules, oneOf)
+			}
+		}
+	case "tinyint", "smallint", "mediumint", "int", "integer", "bigint":
+		if isBoolColumn(ci) {
+			break
+		}
+		if r, ok := integerRanges[ci.DataType]; ok && enabled(ValidateRuleRange) {
+			if ci.IsUnsigned {
+				rules = append(rules, "min="+r[1][0], "max="+r[1][1])
+			} else {
+				rules = append(rules, "min="+r[0][0], "max="+r[0][1])
+			}
+		} else if ci.IsUnsigned && enabled(ValidateRuleUnsigned) {
+			rules = append(rules, "min=0")
+		}
+	case "float", "double", "real", "decimal", "numeric":
+		if ci.IsUnsigned && enabled(ValidateRuleUnsigned) {
+			rules = append(rules, "min=0")
+		}
+	}
+
+	if enabled(ValidateRuleCheck) {
+		for _, check := range ci.Checks {
+			rules = append(rules, parseCheckRules(check.Clause, ci.Name)...)
+		}
+	}
+
+	// omitempty alone makes no sense
+	if len(rules) == 0 || (len(rules) == 1 && rules[0] == "omitempty") {
+		return ""
+	}
+
+	return fmt.Sprintf("validate:%q", strings.Join(rules, ","))
+}
+
+// getOneOfRule returns the oneof rule of the values, or empty string if the
+// values can not be represented by the oneof rule.
+func getOneOfRule(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	items := make([]string, 0, len(values))
+	for _, v := range values {
+		if v == "" || strings.ContainsAny(v, ",|'\"`") {
+			return ""
+		}
+		if strings.ContainsAny(v, " \t") {
+			v = "'" + v + "'"
+		}
+		items = append(items, v)
+	}
+
+	return "oneof=" + strings.Join(items, " ")
+}
+
+// parseEnumValues parses the values of the mysql enum or set column type,
+// such as enum('a','b','c').
+func parseEnumValues(columnType string) []string {
+	start, end := strings.Index(columnType, "("), strings.LastIndex(columnType, ")")
+	if start < 0 || end <= start {
+		return nil
+	}
+
+	var (
+		values  []string
+		value   strings.Builder
+		inQuote bool
+	)
+	s := columnType[start+1 : end]
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\'' && inQuote:
+			if i+1 < len(s) && s[i+1] == '\'' {
+				value.WriteByte(c)
+				i++
+			} else {
+				inQuote = false
+				values = append(values, value.String())
+				value.Reset()
+			}
+		case c == '\'':
+			inQuote = true
+		case inQuote:
+			value.WriteByte(c)
+		}
+	}
+
+	return values
+}
+
+// getCheckColumns returns the unique columns referenced by the clause of the check constraint.
+func getCheckColumns(clause string) []string {
+	var columns []string
+	for _, match := range checkColumnRegexp.FindAllStringSubmatch(clause, -1) {
+		if !containsString(columns, match[1]) {
+			columns = append(columns, match[1])
+		}
+	}
+
+	return columns
+}
+
+// parseCheckRules parses the validator rules of the column from the clause of the check constraint,
+// the comparisons, between, in and length conditions combined by and are supported, the unsupported
+// conditions are skipped.
+func parseCheckRules(clause, column string) []string {
+	clause = strings.ReplaceAll(clause, "`"+column+"`", "?")
+	clause = checkCharsetRegexp.ReplaceAllString(clause, "'")
+
+	var rules []string
+	for _, cond := range splitCheckConditions(clause) {
+		if m := checkCompareRegexp.FindStringSubmatch(cond); m != nil {
+			rules = append(rules, checkOperatorRules[m[1]]+"="+m[2])
+		} else if m = checkReverseRegexp.FindStringSubmatch(cond); m != nil {
+			rules = append(rules, checkOperatorRules[checkReversedOperators[m[2]]]+"="+m[1])
+		} else if m = checkBetweenRegexp.FindStringSubmatch(cond); m != nil {
+			rules = append(rules, "gte="+m[1], "lte="+m[2])
+		} else if m = checkLengthRegexp.FindStringSubmatch(cond); m != nil {
+			rules = append(rules, checkLengthRuleMapping[m[1]]+"="+m[2])
+		} else if m = checkInRegexp.FindStringSubmatch(cond); m != nil {
+			var values []string
+			if checkNumberListRegexp.MatchString(strings.TrimSpace(m[1])) {
+				for _, v := range strings.Split(m[1], ",") {
+					values = append(values, strings.TrimSpace(v))
+				}
+			} else {
+				values = parseEnumValues("(" + m[1] + ")")
+			}
+			if oneOf := getOneOfRule(values); oneOf != "" {
+				rules = append(rules, oneOf)
+			}
+		}
+	}
+
+	return rules
+}
+
+// splitCheckConditions splits the clause of the check constraint into the conditions combined by and,
+// the redundant parentheses wrapping the conditions are removed.
+func splitCheckConditions(clause string) []string {
+	clause = trimCheckParens(clause)
+
+	var (
+		conditions []string
+		depth      int
+		inQuote    bool
+		start      int
+	)
+	lower := strings.ToLower(clause)
+	for i := 0; i < len(clause); i++ {
+		switch c := clause[i]; {
+		case c == '\'':
+			inQuote = !inQuote
+		case inQuote:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case depth == 0 && strings.HasPrefix(lower[i:], " and ") && !isBetweenAnd(lower[start:i]):
+			conditions = append(conditions, trimCheckParens(clause[start:i]))
+			start = i + len(" and ")
+			i = start - 1
+		}
+	}
+	if start == 0 {
+		return []string{clause}
+	}
+
+	return append(conditions, trimCheckParens(clause[start:]))
+}
+
+// isBetweenAnd reports whether the and follows the between condition, such as x between 1 and 2.
+func isBetweenAnd(cond string) bool {
+	fields := strings.Fields(cond)
+	return len(fields) >= 2 && fields[len(fields)-2] == "between"
+}
+
+// trimCheckParens removes the parentheses wrapping the whole condition.
+func trimCheckParens(cond string) string {
+	cond = strings.TrimSpace(cond)
+	for len(cond) >= 2 && cond[0] == '(' && cond[len(cond)-1] == ')' {
+		depth := 0
+		for i := 0; i < len(cond); i++ {
+			if cond[i] == '(' {
+				depth++
+			} else if cond[i] == ')' {
+				depth--
+			}
+			if depth == 0 && i != len(cond)-1 {
+				// the first parenthesis is closed before the end, such as (a) and (b)
+				return cond
+			}
+		}
+		cond = strings.TrimSpace(cond[1 : len(cond)-1])
+	}
+
+	return cond
+}